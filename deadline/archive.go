@@ -0,0 +1,200 @@
+package deadline
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultMaxArchiveSize is the number of decompressed bytes read from an
+// archive if Options.MaxArchiveSize is not set.
+const DefaultMaxArchiveSize = 100 << 20
+
+// maxArchiveDepth limits how deep archives inside archives are opened.
+const maxArchiveDepth = 4
+
+// archiveKind returns the kind of archive of a file by its name, or "" if
+// it is not an archive.
+func archiveKind(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case strings.HasSuffix(lower, ".gz"):
+		return "gz"
+	}
+	switch filepath.Ext(lower) {
+	case ".zip", ".jar", ".war", ".ear":
+		return "zip"
+	}
+	return ""
+}
+
+// archiveScan scans the files in an archive. The findings get virtual
+// paths like bundle.zip!/src/x.go. All decompressed bytes count against
+// one budget, so a zip bomb stops the scan instead of filling the memory.
+type archiveScan struct {
+	s         *Scanner
+	now       time.Time
+	remaining int64
+	limit     int64
+	exceeded  bool
+}
+
+var errArchiveTooLarge = errors.New("archive exceeds the size limit")
+
+func (s *Scanner) newArchiveScan(now time.Time) *archiveScan {
+	limit := s.opts.MaxArchiveSize
+	if limit <= 0 {
+		limit = DefaultMaxArchiveSize
+	}
+	return &archiveScan{s: s, now: now, remaining: limit, limit: limit}
+}
+
+// budget counts the bytes read from r against the size limit.
+func (a *archiveScan) budget(r io.Reader) io.Reader {
+	return &budgetReader{r: r, a: a}
+}
+
+type budgetReader struct {
+	r io.Reader
+	a *archiveScan
+}
+
+func (b *budgetReader) Read(p []byte) (int, error) {
+	if b.a.remaining <= 0 {
+		b.a.exceeded = true
+		return 0, fmt.Errorf("%w of %d bytes", errArchiveTooLarge, b.a.limit)
+	}
+	if int64(len(p)) > b.a.remaining {
+		p = p[:b.a.remaining]
+	}
+	n, err := b.r.Read(p)
+	b.a.remaining -= int64(n)
+	return n, err
+}
+
+// scanFile scans the archive file.
+func (a *archiveScan) scanFile(ctx context.Context, name, rel string, file *os.File) []Finding {
+	if archiveKind(name) == "zip" {
+		info, err := file.Stat()
+		if err != nil {
+			a.s.warn(err)
+			return nil
+		}
+		return a.scanZip(ctx, name, rel, file, info.Size(), 0)
+	}
+	return a.scan(ctx, name, rel, file, 0)
+}
+
+// scan scans the archive with the (virtual) path name read from r.
+func (a *archiveScan) scan(ctx context.Context, name, rel string, r io.Reader, depth int) []Finding {
+	switch archiveKind(name) {
+	case "zip":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			a.fail(name, err)
+			return nil
+		}
+		return a.scanZip(ctx, name, rel, bytes.NewReader(data), int64(len(data)), depth)
+
+	case "tar", "tar.gz":
+		if archiveKind(name) == "tar.gz" {
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				a.fail(name, err)
+				return nil
+			}
+			defer gz.Close()
+			r = gz
+		}
+		return a.scanTar(ctx, name, rel, tar.NewReader(a.budget(r)), depth)
+
+	default:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			a.fail(name, err)
+			return nil
+		}
+		defer gz.Close()
+		inner := gz.Name
+		if inner == "" {
+			inner = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		}
+		return a.entry(ctx, name+"!/"+inner, rel+"!/"+inner, a.budget(gz), depth)
+	}
+}
+
+func (a *archiveScan) scanZip(ctx context.Context, name, rel string, r io.ReaderAt, size int64, depth int) []Finding {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		a.fail(name, err)
+		return nil
+	}
+	var findings []Finding
+	for _, f := range zr.File {
+		if a.exceeded || ctx.Err() != nil {
+			break
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			a.fail(name+"!/"+f.Name, err)
+			continue
+		}
+		findings = append(findings, a.entry(ctx, name+"!/"+f.Name, rel+"!/"+f.Name, a.budget(rc), depth)...)
+		rc.Close()
+	}
+	return findings
+}
+
+func (a *archiveScan) scanTar(ctx context.Context, name, rel string, tr *tar.Reader, depth int) []Finding {
+	var findings []Finding
+	for !a.exceeded && ctx.Err() == nil {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			a.fail(name, err)
+			break
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		findings = append(findings, a.entry(ctx, name+"!/"+hdr.Name, rel+"!/"+hdr.Name, tr, depth)...)
+	}
+	return findings
+}
+
+// entry scans a file in an archive, which may be an archive itself. The
+// bytes of r are already counted against the size limit.
+func (a *archiveScan) entry(ctx context.Context, name, rel string, r io.Reader, depth int) []Finding {
+	if archiveKind(name) == "" {
+		return a.s.scan(ctx, name, rel, r, a.now)
+	}
+	if depth+1 >= maxArchiveDepth {
+		a.s.warn(&ScanError{File: name, Err: fmt.Errorf("archives nested more than %d levels deep are skipped", maxArchiveDepth)})
+		return nil
+	}
+	return a.scan(ctx, name, rel, r, depth+1)
+}
+
+// fail reports an archive which can't be read. Once the size limit is
+// exceeded, the scan stops, so the error is only reported once.
+func (a *archiveScan) fail(name string, err error) {
+	a.s.warn(&ScanError{File: name, Err: err})
+}
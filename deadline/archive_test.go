@@ -0,0 +1,106 @@
+package deadline
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeZip writes a zip archive with the given files to path.
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"a.go", "b.go"} {
+		if content, ok := files[name]; ok {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte(content))
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchiveSizeLimit(t *testing.T) {
+	// The annotation is at the end, so it is only found if the whole file
+	// is read.
+	content := strings.Repeat("// padding\n", 1000) + "// @CHECK(2025-03-01;;;; x)\n"
+	dir := t.TempDir()
+	writeZip(t, filepath.Join(dir, "bundle.zip"), map[string]string{"a.go": content, "b.go": content})
+
+	tests := []struct {
+		limit    int64
+		want     []string
+		exceeded bool
+	}{
+		{0, []string{"bundle.zip!/a.go", "bundle.zip!/b.go"}, false},
+		{int64(len(content)) + 1000, []string{"bundle.zip!/a.go"}, true},
+		{1000, nil, true},
+	}
+	for _, tt := range tests {
+		var exceeded bool
+		s, err := NewScanner(Options{
+			Archives:       true,
+			MaxArchiveSize: tt.limit,
+			Clock:          FixedClock(now),
+			Warn: func(err error) {
+				if errors.Is(err, errArchiveTooLarge) {
+					exceeded = true
+				} else {
+					t.Errorf("limit %d: unexpected warning %v", tt.limit, err)
+				}
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		findings, err := s.ScanDir(context.Background(), dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, f := range findings {
+			rel, _ := filepath.Rel(dir, f.File)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") || exceeded != tt.exceeded {
+			t.Errorf("limit %d: got %v, exceeded %v, want %v, %v", tt.limit, got, exceeded, tt.want, tt.exceeded)
+		}
+	}
+}
+
+func TestArchiveGzippedLog(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("INFO started\nWARN @CHECK(2025-03-01;;;; rotate the logs)\n"))
+	gz.Close()
+	if err := os.WriteFile(filepath.Join(dir, "app.log.gz"), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewScanner(Options{Archives: true, Clock: FixedClock(now)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := s.ScanDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Line != 2 || !strings.HasSuffix(findings[0].File, "app.log.gz!/app.log") {
+		t.Errorf("got %+v", findings)
+	}
+}
@@ -0,0 +1,122 @@
+package deadline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// BaselineFile is the default name of the baseline in the scanned
+// directory.
+const BaselineFile = ".deadline-baseline.json"
+
+// Baseline is a snapshot of the findings which are accepted for now, like
+// the expired annotations of a legacy code base. Findings are matched by
+// their fingerprint, so the baseline survives code moving around.
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry is a finding in the baseline. The fingerprint and the
+// deadline are used for matching, the other fields help reading the file.
+type BaselineEntry struct {
+	Fingerprint string    `json:"fingerprint"`
+	File        string    `json:"file"`
+	Line        int       `json:"line"`
+	Deadline    time.Time `json:"deadline"`
+	Description string    `json:"description,omitempty"`
+}
+
+// NewBaseline returns a baseline of the findings.
+func NewBaseline(findings []Finding) *Baseline {
+	b := &Baseline{Entries: []BaselineEntry{}}
+	for _, f := range findings {
+		b.Entries = append(b.Entries, BaselineEntry{
+			Fingerprint: f.Fingerprint,
+			File:        filepath.ToSlash(f.File),
+			Line:        f.Line,
+			Deadline:    f.Check.Deadline,
+			Description: f.Check.Description,
+		})
+	}
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		if b.Entries[i].File != b.Entries[j].File {
+			return b.Entries[i].File < b.Entries[j].File
+		}
+		return b.Entries[i].Line < b.Entries[j].Line
+	})
+	return b
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline to path.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Apply drops the findings which are in the baseline, except rejected
+// ones. A finding whose deadline was moved since the baseline was written
+// is reported again, so a postponed deadline which expires again isn't
+// hidden. fixed are the entries which no longer match any of the findings,
+// so they can be removed from the baseline.
+func (b *Baseline) Apply(findings []Finding) (remaining []Finding, baselined int, fixed []BaselineEntry) {
+	known := make(map[string]time.Time)
+	for _, e := range b.Entries {
+		known[e.Fingerprint] = e.Deadline
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		deadline, ok := known[f.Fingerprint]
+		if !ok || !deadline.Equal(f.Check.Deadline) {
+			remaining = append(remaining, f)
+			continue
+		}
+		seen[f.Fingerprint] = true
+		if f.Status == StatusRejected {
+			remaining = append(remaining, f)
+			continue
+		}
+		baselined++
+	}
+	for _, e := range b.Entries {
+		if !seen[e.Fingerprint] {
+			fixed = append(fixed, e)
+		}
+	}
+	return remaining, baselined, fixed
+}
+
+// Without returns a copy of the baseline without the given entries.
+func (b *Baseline) Without(entries []BaselineEntry) *Baseline {
+	drop := make(map[string]bool)
+	for _, e := range entries {
+		drop[e.Fingerprint] = true
+	}
+	out := &Baseline{Entries: []BaselineEntry{}}
+	for _, e := range b.Entries {
+		if !drop[e.Fingerprint] {
+			out.Entries = append(out.Entries, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package deadline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blame tells who last changed the line of a finding and when.
+type Blame struct {
+	Commit string
+	Author string
+	Email  string
+	Time   time.Time
+}
+
+// BlameFindings runs git blame for the lines of all findings and attaches
+// the result. If dir is not inside a git work tree, or git is not
+// installed, the findings are left untouched and an error is returned.
+// Files which can't be blamed are skipped, their errors are joined.
+func BlameFindings(ctx context.Context, dir string, findings []Finding) error {
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	if err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return fmt.Errorf("%s is not a git repository, skipping blame", dir)
+	}
+
+	var errs []error
+	byFile := make(map[string][]int)
+	for i, f := range findings {
+		byFile[f.File] = append(byFile[f.File], i)
+	}
+
+	for file, indices := range byFile {
+		lines := make([]int, len(indices))
+		for i, idx := range indices {
+			lines[i] = findings[idx].Line
+		}
+		blames, err := blameLines(ctx, file, lines)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("git blame %s: %w", file, err))
+			continue
+		}
+		for _, idx := range indices {
+			if b, ok := blames[findings[idx].Line]; ok {
+				findings[idx].Blame = b
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// blameLines runs git blame for the given lines of a single file and
+// returns the result by line number.
+func blameLines(ctx context.Context, file string, lines []int) (map[int]*Blame, error) {
+	sort.Ints(lines)
+	args := []string{"-C", filepath.Dir(file), "blame", "--line-porcelain"}
+	for _, l := range lines {
+		args = append(args, "-L", fmt.Sprintf("%d,%d", l, l))
+	}
+	args = append(args, "--", filepath.Base(file))
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseBlame(out), nil
+}
+
+// parseBlame parses the output of git blame --line-porcelain.
+func parseBlame(out []byte) map[int]*Blame {
+	blames := make(map[int]*Blame)
+	var current *Blame
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\t") {
+			// The content of the line ends each entry.
+			current = nil
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if current == nil {
+			// Header: <commit> <original line> <final line> [<group size>]
+			fields := strings.Fields(value)
+			if len(fields) < 2 {
+				continue
+			}
+			final, err := strconv.Atoi(fields[1])
+			if err != nil {
+				continue
+			}
+			current = &Blame{Commit: key}
+			blames[final] = current
+			continue
+		}
+
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.Email = strings.Trim(value, "<>")
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.Time = time.Unix(sec, 0).UTC()
+			}
+		}
+	}
+	return blames
+}
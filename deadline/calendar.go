@@ -0,0 +1,200 @@
+package deadline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Calendar is the content of a calendar file. It resolves relative
+// deadlines like "end of sprint 12" or "release v2.3" and lists the
+// holidays skipped with Options.NextWorkingDay. All dates are written like
+// the date field of an annotation, e.g. 2025-03-14 or 2025-03-14T17:00+01:00.
+type Calendar struct {
+	// Sprints maps the number or name of a sprint to the day it ends.
+	Sprints map[string]string `yaml:"sprints" json:"sprints"`
+	// SprintCadence computes the end of sprints missing in Sprints.
+	SprintCadence *SprintCadence `yaml:"sprint_cadence" json:"sprint_cadence"`
+	// Releases maps a release to its date.
+	Releases map[string]string `yaml:"releases" json:"releases"`
+	Holidays []string          `yaml:"holidays" json:"holidays"`
+}
+
+// SprintCadence describes sprints of equal length: sprint First starts at
+// Start and every sprint ends when the next one starts.
+type SprintCadence struct {
+	First  int    `yaml:"first" json:"first"`
+	Start  string `yaml:"start" json:"start"`
+	Length string `yaml:"length" json:"length"`
+}
+
+// LoadCalendar reads a calendar file in YAML or JSON format.
+func LoadCalendar(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cal Calendar
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, &cal)
+	} else {
+		err = yaml.Unmarshal(data, &cal)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &cal, nil
+}
+
+// absoluteFormats are the layouts accepted for the dates of a calendar and
+// the built-in @CHECK pattern. Without a zone the default zone is used.
+var absoluteFormats = []string{
+	"2006-01-02",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+}
+
+// calendar is a compiled Calendar.
+type calendar struct {
+	sprints  map[string]time.Time
+	releases map[string]time.Time
+	holidays map[string]bool
+
+	cadenceFirst  int
+	cadenceStart  time.Time
+	cadenceLength int // days
+}
+
+func compileCalendar(cal *Calendar, loc *time.Location) (*calendar, error) {
+	c := &calendar{
+		sprints:  make(map[string]time.Time),
+		releases: make(map[string]time.Time),
+		holidays: make(map[string]bool),
+	}
+	if cal == nil {
+		return c, nil
+	}
+
+	for name, s := range cal.Sprints {
+		t, err := parseInLocation(absoluteFormats, s, loc)
+		if err != nil {
+			return nil, fmt.Errorf("sprint %s: invalid date %q", name, s)
+		}
+		c.sprints[strings.ToLower(name)] = t
+	}
+	for name, s := range cal.Releases {
+		t, err := parseInLocation(absoluteFormats, s, loc)
+		if err != nil {
+			return nil, fmt.Errorf("release %s: invalid date %q", name, s)
+		}
+		c.releases[strings.ToLower(name)] = t
+	}
+	for _, s := range cal.Holidays {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q", s)
+		}
+		c.holidays[t.Format("2006-01-02")] = true
+	}
+
+	if sc := cal.SprintCadence; sc != nil {
+		start, err := parseInLocation(absoluteFormats, sc.Start, loc)
+		if err != nil {
+			return nil, fmt.Errorf("sprint_cadence: invalid start %q", sc.Start)
+		}
+		length, err := ParseDuration(sc.Length)
+		if err != nil || length < 24*time.Hour || length%(24*time.Hour) != 0 {
+			return nil, fmt.Errorf("sprint_cadence: length must be a number of days, got %q", sc.Length)
+		}
+		c.cadenceFirst, c.cadenceStart, c.cadenceLength = sc.First, start, int(length/(24*time.Hour))
+	}
+	return c, nil
+}
+
+var (
+	sprintRegex  = regexp.MustCompile(`(?i)^(?:end of )?sprint\s+(\S+)$`)
+	releaseRegex = regexp.MustCompile(`(?i)^release\s+(\S+)$`)
+)
+
+// resolve returns the date of a relative deadline like "end of sprint 12"
+// or "release v2.3". ok is false if s is not a relative deadline.
+func (c *calendar) resolve(s string) (t time.Time, ok bool, err error) {
+	if m := sprintRegex.FindStringSubmatch(s); m != nil {
+		if t, found := c.sprints[strings.ToLower(m[1])]; found {
+			return t, true, nil
+		}
+		var n int
+		if _, err := fmt.Sscanf(m[1], "%d", &n); err == nil && c.cadenceLength > 0 && n >= c.cadenceFirst {
+			return c.cadenceStart.AddDate(0, 0, (n-c.cadenceFirst+1)*c.cadenceLength), true, nil
+		}
+		return time.Time{}, true, fmt.Errorf("unknown sprint %q", m[1])
+	}
+	if m := releaseRegex.FindStringSubmatch(s); m != nil {
+		if t, found := c.releases[strings.ToLower(m[1])]; found {
+			return t, true, nil
+		}
+		return time.Time{}, true, fmt.Errorf("unknown release %q", m[1])
+	}
+	return time.Time{}, false, nil
+}
+
+// nextWorkingDay moves t forward to the next day which is neither on a
+// weekend nor a holiday, keeping the time of day.
+func (c *calendar) nextWorkingDay(t time.Time) time.Time {
+	for t.Weekday() == time.Saturday || t.Weekday() == time.Sunday || c.holidays[t.Format("2006-01-02")] {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// parseInLocation tries all layouts. Dates without a zone are in loc.
+func parseInLocation(layouts []string, s string, loc *time.Location) (time.Time, error) {
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if layout == "iso-week" {
+			if t, err = parseISOWeek(s); err == nil {
+				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+			}
+		} else {
+			t, err = time.ParseInLocation(layout, s, loc)
+		}
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// dateResolver turns the date field of an annotation into a deadline.
+type dateResolver struct {
+	loc            *time.Location
+	calendar       *calendar
+	nextWorkingDay bool
+}
+
+func (r *dateResolver) parse(p *pattern, s string) (time.Time, error) {
+	t, err := parseInLocation(p.dateFormats, s, r.loc)
+	if err != nil {
+		var ok bool
+		t, ok, err = r.calendar.resolve(s)
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid date format %q", s)
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	if r.nextWorkingDay {
+		t = r.calendar.nextWorkingDay(t)
+	}
+	return t, nil
+}
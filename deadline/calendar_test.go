@@ -0,0 +1,122 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseISOWeek(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"2025-W01", "2024-12-30"},
+		{"2025-W05-3", "2025-01-29"},
+		{"2025W053", "2025-01-29"},
+		{"2020-W53-7", "2021-01-03"},
+		{"2026-W01-1", "2025-12-29"},
+	}
+	for _, tt := range tests {
+		got, err := parseISOWeek(tt.in)
+		if err != nil || got.Format("2006-01-02") != tt.want {
+			t.Errorf("parseISOWeek(%q) = %s, %v, want %s", tt.in, got.Format("2006-01-02"), err, tt.want)
+		}
+	}
+	for _, in := range []string{"2025-W54", "2025-W53", "2025-W05-8", "2025-05"} {
+		if _, err := parseISOWeek(in); err == nil {
+			t.Errorf("parseISOWeek(%q) didn't fail", in)
+		}
+	}
+}
+
+func TestISOWeekPattern(t *testing.T) {
+	cfg := &Config{Patterns: []PatternConfig{{
+		Name:        "todo",
+		Regex:       `TODO\((?P<date>[^)]+)\)`,
+		DateFormats: []string{"iso-week"},
+	}}}
+	findings := scanString(t, Options{Config: cfg}, "main.go", "// TODO(2025-W10-5)\n")
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+	if got := findings[0].Check.Deadline.Format("2006-01-02"); got != "2025-03-07" {
+		t.Errorf("got deadline %s, want 2025-03-07", got)
+	}
+	if got := findings[0].Bump(findings[0].Text, 7*24*time.Hour, ""); got != "// TODO(2025-W11-5)" {
+		t.Errorf("bumped to %q", got)
+	}
+}
+
+func TestCalendarDates(t *testing.T) {
+	cal := &Calendar{
+		Sprints:       map[string]string{"Alpha": "2025-02-28"},
+		SprintCadence: &SprintCadence{First: 10, Start: "2025-03-03", Length: "2w"},
+		Releases:      map[string]string{"v2.3": "2025-06-01T17:00+02:00"},
+	}
+	tests := []struct {
+		date    string
+		want    time.Time
+		wantErr bool
+	}{
+		{"end of sprint alpha", time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), false},
+		{"Sprint 10", time.Date(2025, time.March, 17, 0, 0, 0, 0, time.UTC), false},
+		{"sprint 12", time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC), false},
+		{"release v2.3", time.Date(2025, time.June, 1, 15, 0, 0, 0, time.UTC), false},
+		{"sprint 9", time.Time{}, true},
+		{"release v9", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		var errs []error
+		opts := Options{Calendar: cal, Warn: func(err error) { errs = append(errs, err) }}
+		findings := scanString(t, opts, "main.go", "// @CHECK("+tt.date+";;;; x)\n")
+		if tt.wantErr {
+			if len(findings) != 0 || len(errs) != 1 {
+				t.Errorf("%s: got %d findings and errors %v, want one error", tt.date, len(findings), errs)
+			}
+			continue
+		}
+		if len(findings) != 1 || len(errs) != 0 {
+			t.Fatalf("%s: got %d findings and errors %v, want one finding", tt.date, len(findings), errs)
+		}
+		if got := findings[0].Check.Deadline; !got.Equal(tt.want) {
+			t.Errorf("%s: got %s, want %s", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestNextWorkingDay(t *testing.T) {
+	cal := &Calendar{Holidays: []string{"2025-04-18", "2025-04-21"}}
+	tests := []struct {
+		date, want string
+	}{
+		{"2025-03-12", "2025-03-12"},
+		{"2025-03-15", "2025-03-17"},
+		{"2025-03-16", "2025-03-17"},
+		{"2025-04-18", "2025-04-22"},
+		{"2025-04-19T17:00", "2025-04-22T17:00"},
+	}
+	for _, tt := range tests {
+		findings := scanString(t, Options{Calendar: cal, NextWorkingDay: true}, "main.go", "// @CHECK("+tt.date+";;;; x)\n")
+		if len(findings) != 1 {
+			t.Fatalf("%s: got %d findings, want 1", tt.date, len(findings))
+		}
+		got := findings[0].Check.Deadline.Format("2006-01-02T15:04")
+		if got != tt.want && got != tt.want+"T00:00" {
+			t.Errorf("%s: got %s, want %s", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestCompileCalendarErrors(t *testing.T) {
+	tests := []*Calendar{
+		{Sprints: map[string]string{"1": "tomorrow"}},
+		{Releases: map[string]string{"v1": "2025-13-01"}},
+		{Holidays: []string{"2025-04-18T00:00"}},
+		{SprintCadence: &SprintCadence{Start: "2025-03-03", Length: "36h"}},
+	}
+	for _, cal := range tests {
+		if _, err := NewScanner(Options{Calendar: cal}); err == nil {
+			t.Errorf("NewScanner with %+v didn't fail", cal)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+// runBaseline writes the current expired and expiring findings to the
+// baseline file, or with prune only removes the entries which are fixed.
+func runBaseline(args []string) int {
+	if len(args) == 0 || (args[0] != "write" && args[0] != "prune") {
+		fmt.Fprintln(os.Stderr, "Usage: deadline baseline write|prune [flags]")
+		return exitUsage
+	}
+	sub := args[0]
+
+	fs := flag.NewFlagSet("baseline "+sub, flag.ExitOnError)
+	sf := addScanFlags(fs)
+	file := fs.String("file", "", "Baseline file (default: "+deadline.BaselineFile+" in -dir)")
+	fs.Parse(args[1:])
+
+	path := *file
+	if path == "" {
+		path = filepath.Join(sf.dir, deadline.BaselineFile)
+	}
+
+	findings, code := scanInventory(sf)
+	if code != exitOK {
+		return code
+	}
+	findings = withoutStatus(findings, deadline.StatusOK)
+
+	b := deadline.NewBaseline(findings)
+	if sub == "prune" {
+		old, err := deadline.LoadBaseline(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitExpired
+		}
+		_, _, fixed := old.Apply(findings)
+		b = old.Without(fixed)
+		fmt.Fprintf(os.Stderr, "Removed %d fixed entries from %s\n", len(fixed), path)
+	} else {
+		fmt.Fprintf(os.Stderr, "Wrote %d entries to %s\n", len(b.Entries), path)
+	}
+
+	if err := b.Save(path); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	return exitOK
+}
+
+// loadBaseline loads the baseline given with -baseline, or the default
+// baseline file in dir if it exists. It returns nil without a baseline.
+func loadBaseline(path, dir string) (*deadline.Baseline, error) {
+	if path == "" {
+		path = filepath.Join(dir, deadline.BaselineFile)
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
+	}
+	return deadline.LoadBaseline(path)
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+// issue is a ticket for an expired or expiring finding. The fingerprint
+// label lets trackers find the ticket again on the next run.
+type issue struct {
+	Title       string
+	Body        string
+	Labels      []string
+	DueDate     string
+	Finding     deadline.Finding
+	Fingerprint string
+}
+
+// fingerprintLabel is the label marking the ticket of a finding.
+func fingerprintLabel(fp string) string {
+	return "deadline-" + fp
+}
+
+func newIssue(f deadline.Finding) issue {
+	what := f.Check.Description
+	if what == "" {
+		what = fmt.Sprintf("%s:%d", filepath.ToSlash(f.File), f.Line)
+	}
+	title := "Deadline exceeded: " + what
+	if f.Status == deadline.StatusExpiring {
+		title = "Deadline expiring: " + what
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s.\n\n", message(f))
+	fmt.Fprintf(&body, "* File: `%s:%d`\n", filepath.ToSlash(f.File), f.Line)
+	fmt.Fprintf(&body, "* Deadline: %s\n", formatDeadline(f.Check.Deadline))
+	fmt.Fprintf(&body, "* Severity: %s\n", f.Check.Severity)
+	if f.Check.Owner != "" {
+		fmt.Fprintf(&body, "* Owner: %s\n", f.Check.Owner)
+	}
+	if len(f.Check.Tags) > 0 {
+		fmt.Fprintf(&body, "* Tags: %s\n", strings.Join(f.Check.Tags, ", "))
+	}
+	fmt.Fprintf(&body, "\n```\n%s\n```\n\n<!-- deadline-fingerprint: %s -->\n", strings.TrimSpace(f.Text), f.Fingerprint)
+
+	labels := []string{"deadline", fingerprintLabel(f.Fingerprint)}
+	labels = append(labels, f.Check.Tags...)
+	return issue{
+		Title:       title,
+		Body:        body.String(),
+		Labels:      labels,
+		DueDate:     f.Check.Deadline.Format("2006-01-02"),
+		Finding:     f,
+		Fingerprint: f.Fingerprint,
+	}
+}
+
+// exporters maps the values of the -to flag to the function writing the
+// issues in that format. The markdown exporter also gets the previous
+// content of the output file, if any.
+var exporters = map[string]func(w io.Writer, issues []issue, previous []byte) error{
+	"github":   writeGitHubIssues,
+	"gitlab":   writeGitLabIssues,
+	"jira":     writeJiraCSV,
+	"markdown": writeMarkdownChecklist,
+}
+
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	sf := addScanFlags(fs)
+	to := fs.String("to", "markdown", "Issue format: github, gitlab, jira or markdown")
+	output := fs.String("o", "", "Write to this file instead of stdout; a markdown checklist keeps the checked items")
+	upload := fs.Bool("upload", false, "Create or update the issues through the API of -to (github or gitlab)")
+	apiURL := fs.String("api", "", "API base URL (default: https://api.github.com or https://gitlab.com/api/v4)")
+	repo := fs.String("repo", "", "Repository to upload to: owner/name on GitHub, the project ID or path on GitLab")
+	token := fs.String("token", "", "API token (default: $GITHUB_TOKEN or $GITLAB_TOKEN)")
+	fs.Parse(args)
+
+	export, ok := exporters[*to]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown -to: %s\n", *to)
+		return exitUsage
+	}
+	var up *uploader
+	if *upload {
+		var err error
+		if up, err = newUploader(*to, *apiURL, *repo, *token); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+	}
+
+	findings, code := scanInventory(sf)
+	if code != exitOK {
+		return code
+	}
+	var issues []issue
+	for _, f := range withoutStatus(findings, deadline.StatusOK) {
+		issues = append(issues, newIssue(f))
+	}
+
+	if up != nil {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		created, updated, err := up.upload(ctx, issues)
+		fmt.Fprintf(os.Stderr, "Created %d and updated %d issues\n", created, updated)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitExpired
+		}
+		return exitOK
+	}
+
+	if *output == "" {
+		if err := export(os.Stdout, issues, nil); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitExpired
+		}
+		return exitOK
+	}
+
+	previous, err := os.ReadFile(*output)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	var buf strings.Builder
+	if err := export(&buf, issues, previous); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	if err := os.WriteFile(*output, []byte(buf.String()), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	return exitOK
+}
+
+// gitHubIssue is the payload of the GitHub issues API.
+type gitHubIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+// gitLabIssue is the payload of the GitLab issues API, which takes the
+// labels as a comma separated list.
+type gitLabIssue struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Labels      string `json:"labels"`
+	DueDate     string `json:"due_date"`
+}
+
+func toGitHubIssue(is issue) gitHubIssue {
+	return gitHubIssue{Title: is.Title, Body: is.Body, Labels: is.Labels}
+}
+
+func toGitLabIssue(is issue) gitLabIssue {
+	return gitLabIssue{Title: is.Title, Description: is.Body, Labels: strings.Join(is.Labels, ","), DueDate: is.DueDate}
+}
+
+func writeGitHubIssues(w io.Writer, issues []issue, _ []byte) error {
+	out := []gitHubIssue{}
+	for _, is := range issues {
+		out = append(out, toGitHubIssue(is))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeGitLabIssues(w io.Writer, issues []issue, _ []byte) error {
+	out := []gitLabIssue{}
+	for _, is := range issues {
+		out = append(out, toGitLabIssue(is))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// writeJiraCSV writes a file for the Jira CSV importer. Jira takes
+// multiple labels as repeated Labels columns. The fingerprint is also the
+// External ID, so a new import can update the issues of the last one.
+func writeJiraCSV(w io.Writer, issues []issue, _ []byte) error {
+	maxLabels := 0
+	for _, is := range issues {
+		maxLabels = max(maxLabels, len(is.Labels))
+	}
+
+	cw := csv.NewWriter(w)
+	header := []string{"External ID", "Summary", "Issue Type", "Priority", "Due Date", "Description"}
+	for i := 0; i < maxLabels; i++ {
+		header = append(header, "Labels")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, is := range issues {
+		record := []string{is.Fingerprint, is.Title, "Task", jiraPriority(is.Finding), is.DueDate, is.Body}
+		for i := 0; i < maxLabels; i++ {
+			label := ""
+			if i < len(is.Labels) {
+				// Jira labels can't contain spaces.
+				label = strings.ReplaceAll(is.Labels[i], " ", "_")
+			}
+			record = append(record, label)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func jiraPriority(f deadline.Finding) string {
+	switch {
+	case f.Check.Severity == deadline.SeverityInfo:
+		return "Low"
+	case f.Check.Severity == deadline.SeverityWarn || f.Status == deadline.StatusExpiring:
+		return "Medium"
+	default:
+		return "High"
+	}
+}
+
+var checklistItemRegex = regexp.MustCompile(`^- \[([ xX])\] .*<!-- deadline-fingerprint: (\S+) -->$`)
+
+// writeMarkdownChecklist writes one checklist item per issue. Items
+// checked in the previous version of the file stay checked.
+func writeMarkdownChecklist(w io.Writer, issues []issue, previous []byte) error {
+	checked := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(string(previous)))
+	for scanner.Scan() {
+		if m := checklistItemRegex.FindStringSubmatch(scanner.Text()); m != nil && m[1] != " " {
+			checked[m[2]] = true
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "# Deadlines\n\n"); err != nil {
+		return err
+	}
+	for _, is := range issues {
+		box := " "
+		if checked[is.Fingerprint] {
+			box = "x"
+		}
+		f := is.Finding
+		item := fmt.Sprintf("- [%s] **%s** – `%s:%d`, due %s", box, is.Title, filepath.ToSlash(f.File), f.Line, formatDeadline(f.Check.Deadline))
+		if f.Check.Owner != "" {
+			item += ", owner " + f.Check.Owner
+		}
+		if _, err := fmt.Fprintf(w, "%s <!-- deadline-fingerprint: %s -->\n", item, is.Fingerprint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+// edit replaces line Line of a file with Text, or removes it if Delete is
+// set.
+type edit struct {
+	Line   int
+	Old    string
+	Text   string
+	Delete bool
+}
+
+func runBump(args []string) int {
+	fs := flag.NewFlagSet("bump", flag.ExitOnError)
+	sf := addScanFlags(fs)
+	by := fs.String("by", "", "Postpone the deadlines by this duration, e.g. 30d or 2w")
+	match := fs.String("match", "", "Only postpone annotations with this tag")
+	expired := fs.Bool("expired", false, "Only postpone expired (and with -warn-within expiring) annotations")
+	reason := fs.String("reason", "", "Reason recorded in the description of the annotations")
+	dryRun := fs.Bool("dry-run", false, "Print a diff instead of changing the files")
+	fs.Parse(args)
+
+	d, err := deadline.ParseDuration(*by)
+	if err != nil || d <= 0 {
+		fmt.Fprintln(os.Stderr, "bump needs a positive -by duration, e.g. -by 30d")
+		return exitUsage
+	}
+	if *match == "" && !*expired {
+		fmt.Fprintln(os.Stderr, "bump needs -match <tag> or -expired")
+		return exitUsage
+	}
+
+	findings, code := scanInventory(sf)
+	if code != exitOK {
+		return code
+	}
+
+	var selected []deadline.Finding
+	for _, f := range findings {
+		if *match != "" && !hasTag(f, *match) {
+			continue
+		}
+		if *expired && f.Status == deadline.StatusOK {
+			continue
+		}
+		selected = append(selected, f)
+	}
+
+	note := "postponed by " + deadline.FormatDuration(d)
+	if *reason != "" {
+		note += ": " + *reason
+	}
+	return applyEdits(os.Stdout, selected, *dryRun, func(f deadline.Finding, line string) (string, bool) {
+		return f.Bump(line, d, note), false
+	})
+}
+
+func runResolve(args []string) int {
+	fs := flag.NewFlagSet("resolve", flag.ExitOnError)
+	sf := addScanFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "Print a diff instead of changing the files")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deadline resolve [flags] <file:line>...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	targets := make(map[string]map[int]bool)
+	for _, arg := range fs.Args() {
+		i := strings.LastIndex(arg, ":")
+		line, err := strconv.Atoi(arg[i+1:])
+		if i < 0 || err != nil {
+			fmt.Fprintf(os.Stderr, "invalid location %q, expected <file:line>\n", arg)
+			return exitUsage
+		}
+		file := filepath.Clean(arg[:i])
+		if targets[file] == nil {
+			targets[file] = make(map[int]bool)
+		}
+		targets[file][line] = true
+	}
+
+	scanner, err := sf.scanner(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	files := make([]string, 0, len(targets))
+	for file := range targets {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	findings, err := scanner.ScanFiles(ctx, sf.dir, files)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+
+	var selected []deadline.Finding
+	found := make(map[string]bool)
+	for _, f := range findings {
+		if targets[filepath.Clean(f.File)][f.Line] {
+			selected = append(selected, f)
+			found[fmt.Sprintf("%s:%d", filepath.Clean(f.File), f.Line)] = true
+		}
+	}
+	for _, file := range files {
+		for line := range targets[file] {
+			if loc := fmt.Sprintf("%s:%d", file, line); !found[loc] {
+				fmt.Fprintf(os.Stderr, "no annotation found at %s\n", loc)
+			}
+		}
+	}
+
+	return applyEdits(os.Stdout, selected, *dryRun, deadline.Finding.Remove)
+}
+
+func hasTag(f deadline.Finding, tag string) bool {
+	for _, t := range f.Check.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEdits rewrites the annotations of the findings with rewrite. Files
+// are replaced atomically, or a diff is printed to w with dryRun.
+func applyEdits(w io.Writer, findings []deadline.Finding, dryRun bool, rewrite func(f deadline.Finding, line string) (string, bool)) int {
+	byFile := make(map[string][]deadline.Finding)
+	for _, f := range findings {
+		byFile[f.File] = append(byFile[f.File], f)
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	code := exitOK
+	changed := 0
+	for _, file := range files {
+		// Rewrite annotations from right to left, so the offsets of the
+		// other annotations on the same line stay valid.
+		fileFindings := byFile[file]
+		sort.SliceStable(fileFindings, func(i, j int) bool {
+			if fileFindings[i].Line != fileFindings[j].Line {
+				return fileFindings[i].Line < fileFindings[j].Line
+			}
+			return fileFindings[i].Column > fileFindings[j].Column
+		})
+
+		n, err := editFile(w, file, fileFindings, dryRun, rewrite)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error editing file: %s, error: %v\n", file, err)
+			code = exitExpired
+		}
+		changed += n
+	}
+
+	verb := "Changed"
+	if dryRun {
+		verb = "Would change"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d annotations in %d files\n", verb, changed, len(files))
+	return code
+}
+
+func editFile(w io.Writer, file string, findings []deadline.Finding, dryRun bool, rewrite func(f deadline.Finding, line string) (string, bool)) (int, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	lines := splitLines(data)
+
+	n := 0
+	edits := make(map[int]*edit)
+	for _, f := range findings {
+		if f.Line > len(lines) {
+			return 0, fmt.Errorf("line %d not found", f.Line)
+		}
+		content, _ := cutLineEnding(lines[f.Line-1])
+		e := edits[f.Line]
+		if e == nil {
+			if content != f.Text {
+				return 0, fmt.Errorf("line %d changed while scanning", f.Line)
+			}
+			e = &edit{Line: f.Line, Old: content, Text: content}
+			edits[f.Line] = e
+		}
+		if e.Delete {
+			continue
+		}
+		text, del := rewrite(f, e.Text)
+		if del || text != e.Text {
+			n++
+		}
+		e.Text, e.Delete = text, del
+	}
+
+	var sorted []*edit
+	for _, e := range edits {
+		if e.Delete || e.Text != e.Old {
+			sorted = append(sorted, e)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Line < sorted[j].Line })
+	if len(sorted) == 0 {
+		return 0, nil
+	}
+
+	if dryRun {
+		return n, writeEditDiff(w, file, sorted)
+	}
+
+	var buf bytes.Buffer
+	for i, line := range lines {
+		e := edits[i+1]
+		if e == nil {
+			buf.Write(line)
+			continue
+		}
+		if e.Delete {
+			continue
+		}
+		_, ending := cutLineEnding(line)
+		buf.WriteString(e.Text)
+		buf.Write(ending)
+	}
+	return n, writeFileAtomic(file, buf.Bytes())
+}
+
+// splitLines splits data after each \n, keeping the line endings.
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			lines = append(lines, data)
+			break
+		}
+		lines = append(lines, data[:i+1])
+		data = data[i+1:]
+	}
+	return lines
+}
+
+// cutLineEnding splits a line into its content and its \n or \r\n ending.
+func cutLineEnding(line []byte) (string, []byte) {
+	n := len(line)
+	switch {
+	case bytes.HasSuffix(line, []byte("\r\n")):
+		n -= 2
+	case bytes.HasSuffix(line, []byte("\n")):
+		n--
+	}
+	return string(line[:n]), line[n:]
+}
+
+// writeEditDiff prints the edits as a unified diff without context lines.
+func writeEditDiff(w io.Writer, file string, edits []*edit) error {
+	name := filepath.ToSlash(file)
+	if _, err := fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", name, name); err != nil {
+		return err
+	}
+	delta := 0
+	for _, e := range edits {
+		var err error
+		if e.Delete {
+			_, err = fmt.Fprintf(w, "@@ -%d +%d,0 @@\n-%s\n", e.Line, e.Line+delta-1, e.Old)
+			delta--
+		} else {
+			_, err = fmt.Fprintf(w, "@@ -%d +%d @@\n-%s\n+%s\n", e.Line, e.Line+delta, e.Old, e.Text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic replaces file with data by renaming a temporary file, so
+// readers never see a partially written file. The permissions are kept.
+func writeFileAtomic(file string, data []byte) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
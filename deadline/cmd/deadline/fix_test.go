@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+func TestEditFileKeepsLineEndings(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{
+			"lf",
+			"a\n// @CHECK(2025-03-01;;;; x)\nb",
+			"a\n// @CHECK(2025-03-31;;;; x (postponed by 30d))\nb",
+		},
+		{
+			"crlf",
+			"a\r\n// @CHECK(2025-03-01;;;; x)\r\nb\r\n",
+			"a\r\n// @CHECK(2025-03-31;;;; x (postponed by 30d))\r\nb\r\n",
+		},
+		{
+			"no final newline",
+			"a\r\n// @CHECK(2025-03-01;;;; x)",
+			"a\r\n// @CHECK(2025-03-31;;;; x (postponed by 30d))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "main.go")
+			if err := os.WriteFile(file, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			s, err := deadline.NewScanner(deadline.Options{Clock: deadline.FixedClock(time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC))})
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, err := s.ScanDir(context.Background(), file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n, err := editFile(nil, file, findings, false, func(f deadline.Finding, line string) (string, bool) {
+				return f.Bump(line, 30*24*time.Hour, "postponed by 30d"), false
+			})
+			if err != nil || n != 1 {
+				t.Fatalf("got %d changes, %v", n, err)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, []byte(tt.want)) {
+				t.Errorf("got  %q\nwant %q", data, tt.want)
+			}
+			info, err := os.Stat(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0o600 {
+				t.Errorf("got permissions %v, want 0600", perm)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies a pre-commit hook written by install-hook, so it
+// can be replaced without -force.
+const hookMarker = "# Installed by deadline install-hook."
+
+// runInstallHook writes a git pre-commit hook which checks the staged
+// files. Arguments after the flags are passed on to deadline check.
+func runInstallHook(args []string) int {
+	fs := flag.NewFlagSet("install-hook", flag.ExitOnError)
+	dir := fs.String("dir", ".", "The git repository to install the hook in")
+	command := fs.String("command", "deadline", "The command the hook runs")
+	force := fs.Bool("force", false, "Replace an existing pre-commit hook")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: deadline install-hook [flags] [-- check flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", *dir, "rev-parse", "--path-format=absolute", "--git-path", "hooks/pre-commit")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s is not a git repository: %s\n", *dir, strings.TrimSpace(stderr.String()))
+		return exitUsage
+	}
+	hook := strings.TrimSpace(string(out))
+
+	if old, err := os.ReadFile(hook); err == nil && !*force && !bytes.Contains(old, []byte(hookMarker)) {
+		fmt.Fprintf(os.Stderr, "%s already exists, use -force to replace it\n", hook)
+		return exitUsage
+	}
+
+	words := []string{shellQuote(*command), "check", "-staged"}
+	for _, arg := range fs.Args() {
+		words = append(words, shellQuote(arg))
+	}
+	script := fmt.Sprintf("#!/bin/sh\n%s\n# Checks the deadlines in the staged files, skip with git commit --no-verify.\nexec %s\n", hookMarker, strings.Join(words, " "))
+
+	if err := os.MkdirAll(filepath.Dir(hook), 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	if err := os.WriteFile(hook, []byte(script), 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	// WriteFile keeps the mode of an existing file.
+	if err := os.Chmod(hook, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	fmt.Fprintf(os.Stderr, "Installed %s\n", hook)
+	return exitOK
+}
+
+// shellQuote quotes s for /bin/sh if needed.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,@") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+// listSorters order the findings of the list command.
+var listSorters = map[string]func(a, b deadline.Finding) bool{
+	"date": func(a, b deadline.Finding) bool {
+		return a.Check.Deadline.Before(b.Check.Deadline)
+	},
+	"owner": func(a, b deadline.Finding) bool {
+		return lessEmptyLast(a.Check.Owner, b.Check.Owner)
+	},
+	"tag": func(a, b deadline.Finding) bool {
+		return lessEmptyLast(firstTag(a), firstTag(b))
+	},
+	"file": func(a, b deadline.Finding) bool {
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	},
+}
+
+// lessEmptyLast compares case insensitive and sorts empty strings last.
+func lessEmptyLast(a, b string) bool {
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+func firstTag(f deadline.Finding) string {
+	if len(f.Check.Tags) == 0 {
+		return ""
+	}
+	tags := append([]string(nil), f.Check.Tags...)
+	sort.Strings(tags)
+	return tags[0]
+}
+
+// scanInventory scans the directory of the list and stats commands for all
+// annotations.
+func scanInventory(sf *scanFlags) ([]deadline.Finding, int) {
+	scanner, err := sf.scanner(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, exitUsage
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	findings, err := scanner.ScanDir(ctx, sf.dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, exitExpired
+	}
+	return findings, exitOK
+}
+
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	sf := addScanFlags(fs)
+	sortBy := fs.String("sort", "date", "Sort by: date, owner, tag or file")
+	format := fs.String("format", "text", "Output format: text or json")
+	fs.Parse(args)
+
+	less, ok := listSorters[*sortBy]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown -sort: %s\n", *sortBy)
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format: %s\n", *format)
+		return exitUsage
+	}
+
+	findings, code := scanInventory(sf)
+	if code != exitOK {
+		return code
+	}
+
+	// findings are sorted by file and line already, a stable sort keeps
+	// that order for equal keys.
+	sort.SliceStable(findings, func(i, j int) bool {
+		return less(findings[i], findings[j])
+	})
+
+	write := writeList
+	if *format == "json" {
+		write = writeJSON
+	}
+	if err := write(os.Stdout, findings); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	return exitOK
+}
+
+func writeList(w io.Writer, findings []deadline.Finding) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DEADLINE\tSTATUS\tSEVERITY\tOWNER\tTAGS\tLOCATION\tDESCRIPTION")
+	for _, f := range findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s:%d\t%s\n",
+			formatDeadline(f.Check.Deadline), f.Status, f.Check.Severity, f.Check.Owner,
+			strings.Join(f.Check.Tags, ","), f.File, f.Line, f.Check.Description)
+	}
+	return tw.Flush()
+}
+
+// ageBuckets group overdue findings by the number of days they are overdue.
+var ageBuckets = []struct {
+	label   string
+	maxDays int
+}{
+	{"< 1 week", 7},
+	{"1-4 weeks", 28},
+	{"1-3 months", 91},
+	{"3-12 months", 365},
+	{"> 1 year", -1},
+}
+
+// stats are the numbers reported by the stats command.
+type stats struct {
+	Total        int            `json:"total"`
+	Expired      int            `json:"expired"`
+	Expiring     int            `json:"expiring"`
+	PerMonth     map[string]int `json:"per_month"`
+	PerTag       map[string]int `json:"per_tag"`
+	PerDirectory map[string]int `json:"per_directory"`
+	OverdueAge   map[string]int `json:"overdue_age"`
+}
+
+func computeStats(findings []deadline.Finding) stats {
+	s := stats{
+		Total:        len(findings),
+		PerMonth:     make(map[string]int),
+		PerTag:       make(map[string]int),
+		PerDirectory: make(map[string]int),
+		OverdueAge:   make(map[string]int),
+	}
+	for _, b := range ageBuckets {
+		s.OverdueAge[b.label] = 0
+	}
+
+	for _, f := range findings {
+		s.PerMonth[f.Check.Deadline.Format("2006-01")]++
+		for _, tag := range f.Check.Tags {
+			s.PerTag[tag]++
+		}
+		if len(f.Check.Tags) == 0 {
+			s.PerTag["(none)"]++
+		}
+		s.PerDirectory[filepath.ToSlash(filepath.Dir(f.File))]++
+
+		switch f.Status {
+		case deadline.StatusExpiring:
+			s.Expiring++
+		case deadline.StatusExpired:
+			s.Expired++
+			for _, b := range ageBuckets {
+				if b.maxDays < 0 || f.DaysOverdue < b.maxDays {
+					s.OverdueAge[b.label]++
+					break
+				}
+			}
+		}
+	}
+	return s
+}
+
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	sf := addScanFlags(fs)
+	format := fs.String("format", "text", "Output format: text or json")
+	fs.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format: %s\n", *format)
+		return exitUsage
+	}
+
+	findings, code := scanInventory(sf)
+	if code != exitOK {
+		return code
+	}
+
+	s := computeStats(findings)
+	var err error
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(s)
+	} else {
+		err = writeStats(os.Stdout, s)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	return exitOK
+}
+
+func writeStats(w io.Writer, s stats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Annotations:\t%d\n", s.Total)
+	fmt.Fprintf(tw, "Expired:\t%d\n", s.Expired)
+	fmt.Fprintf(tw, "Expiring:\t%d\n", s.Expiring)
+
+	section := func(title string, counts map[string]int, keys []string) {
+		fmt.Fprintf(tw, "\n%s:\n", title)
+		for _, k := range keys {
+			fmt.Fprintf(tw, "  %s\t%d\n", k, counts[k])
+		}
+	}
+	section("Per month", s.PerMonth, sortedCountKeys(s.PerMonth, false))
+	section("Per tag", s.PerTag, sortedCountKeys(s.PerTag, true))
+	section("Per directory", s.PerDirectory, sortedCountKeys(s.PerDirectory, true))
+
+	var ages []string
+	for _, b := range ageBuckets {
+		ages = append(ages, b.label)
+	}
+	section("Overdue age", s.OverdueAge, ages)
+	return tw.Flush()
+}
+
+// sortedCountKeys returns the keys of counts sorted alphabetically or, if
+// byCount is set, by descending count.
+func sortedCountKeys(counts map[string]int, byCount bool) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if byCount && counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+func TestListSorters(t *testing.T) {
+	findings := []deadline.Finding{
+		{File: "b.go", Line: 1},
+		{File: "a.go", Line: 9},
+		{File: "a.go", Line: 2},
+	}
+	less := listSorters["file"]
+	for i, tt := range []struct{ a, b int }{{2, 1}, {1, 0}, {2, 0}} {
+		if !less(findings[tt.a], findings[tt.b]) || less(findings[tt.b], findings[tt.a]) {
+			t.Errorf("case %d: %v is not sorted before %v", i, findings[tt.a], findings[tt.b])
+		}
+	}
+}
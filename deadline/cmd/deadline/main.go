@@ -0,0 +1,327 @@
+// Description: A tool to check for deadlines in source code.
+// It searches for the @CHECK annotation and checks if the deadline has passed.
+// If it has, it prints the line and returns an error.
+// The scanner itself is the importable package github.com/SimonWaldherr/gotools/deadline.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+// Exit codes of the tool. The flag package already uses 2 for usage errors.
+const (
+	exitOK       = 0
+	exitExpired  = 1
+	exitUsage    = 2
+	exitExpiring = 3
+)
+
+// commands are the subcommands of the tool. Without a subcommand, check
+// is run.
+var commands = map[string]func(args []string) int{
+	"check":        runCheck,
+	"list":         runList,
+	"stats":        runStats,
+	"bump":         runBump,
+	"resolve":      runResolve,
+	"export":       runExport,
+	"baseline":     runBaseline,
+	"watch":        runWatch,
+	"install-hook": runInstallHook,
+}
+
+func main() {
+	args := os.Args[1:]
+	name := "check"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\nUsage: deadline [check|list|stats|bump|resolve|export|baseline|watch|install-hook] [flags]\n", name)
+		os.Exit(exitUsage)
+	}
+	os.Exit(cmd(args))
+}
+
+// scanFlags are the flags shared by all commands which scan a directory.
+type scanFlags struct {
+	dir               string
+	warnWithin        string
+	configFile        string
+	include           stringList
+	exclude           stringList
+	noDefaultExcludes bool
+	unknownAsText     bool
+	workers           int
+	timezone          string
+	calendarFile      string
+	nextWorkingDay    bool
+	archives          bool
+	maxArchiveSize    string
+}
+
+func addScanFlags(fs *flag.FlagSet) *scanFlags {
+	sf := &scanFlags{}
+	fs.StringVar(&sf.dir, "dir", ".", "The directory to search for deadlines")
+	fs.StringVar(&sf.warnWithin, "warn-within", "0", "Also report deadlines expiring within this duration, e.g. 14d, 2w or 36h")
+	fs.Var(&sf.include, "include", "Only scan files matching this glob (repeatable)")
+	fs.Var(&sf.exclude, "exclude", "Skip files and directories matching this glob (repeatable)")
+	fs.BoolVar(&sf.noDefaultExcludes, "no-default-excludes", false, "Don't skip .git, node_modules and vendor directories")
+	fs.IntVar(&sf.workers, "workers", runtime.NumCPU(), "Number of files scanned in parallel")
+	fs.BoolVar(&sf.unknownAsText, "unknown-as-text", false, "Match annotations anywhere in files of unknown type instead of skipping them")
+	fs.StringVar(&sf.configFile, "config", "", "Config file (default: .deadline.yaml, .deadline.yml or .deadline.json in -dir)")
+	fs.StringVar(&sf.timezone, "timezone", "", "Zone of dates written without one, e.g. Europe/Berlin (default: from the config or UTC)")
+	fs.StringVar(&sf.calendarFile, "calendar", "", "Calendar file resolving sprints, releases and holidays (default: from the config)")
+	fs.BoolVar(&sf.nextWorkingDay, "next-working-day", false, "Move deadlines on weekends and holidays to the next working day")
+	fs.BoolVar(&sf.archives, "archives", false, "Also scan the files in .zip, .jar, .tar, .tar.gz and .gz files, matched by type like other files (.log and .txt are text, unknown types need -unknown-as-text)")
+	fs.StringVar(&sf.maxArchiveSize, "max-archive-size", "100MB", "Stop reading an archive after this many decompressed bytes, e.g. 500KB, 100MB or 1GB")
+	return sf
+}
+
+// options returns the scanner options after the flags have been parsed.
+// Warnings are printed to stderr.
+func (sf *scanFlags) options() (deadline.Options, error) {
+	opts := deadline.Options{
+		Include:           sf.include,
+		Exclude:           sf.exclude,
+		NoDefaultExcludes: sf.noDefaultExcludes,
+		UnknownAsText:     sf.unknownAsText,
+		Workers:           sf.workers,
+		NextWorkingDay:    sf.nextWorkingDay,
+		Archives:          sf.archives,
+		Warn:              func(err error) { fmt.Fprintln(os.Stderr, err) },
+	}
+	within, err := deadline.ParseDuration(sf.warnWithin)
+	if err != nil {
+		return opts, fmt.Errorf("invalid -warn-within: %v", err)
+	}
+	opts.WarnWithin = within
+	if opts.MaxArchiveSize, err = parseSize(sf.maxArchiveSize); err != nil {
+		return opts, fmt.Errorf("invalid -max-archive-size: %v", err)
+	}
+	if sf.timezone != "" {
+		if opts.Location, err = time.LoadLocation(sf.timezone); err != nil {
+			return opts, fmt.Errorf("invalid -timezone: %v", err)
+		}
+	}
+	if sf.calendarFile != "" {
+		if opts.Calendar, err = deadline.LoadCalendar(sf.calendarFile); err != nil {
+			return opts, err
+		}
+	}
+	opts.Config, err = deadline.LoadConfig(sf.configFile, sf.dir)
+	return opts, err
+}
+
+// formatDeadline prints a deadline as a day, or with its time of day and
+// zone if it doesn't expire at midnight.
+func formatDeadline(t time.Time) string {
+	if h, m, s := t.Clock(); h == 0 && m == 0 && s == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02T15:04Z07:00")
+}
+
+// scanner returns a scanner for the flags, adjusted by configure.
+func (sf *scanFlags) scanner(configure func(*deadline.Options)) (*deadline.Scanner, error) {
+	opts, err := sf.options()
+	if err != nil {
+		return nil, err
+	}
+	if configure != nil {
+		configure(&opts)
+	}
+	return deadline.NewScanner(opts)
+}
+
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	sf := addScanFlags(fs)
+	format := fs.String("format", "text", "Output format: text, json, sarif, junit or checkstyle")
+	blame := fs.Bool("blame", false, "Run git blame to find the author of each finding")
+	groupBy := fs.String("group-by", "", "Group the report: author (implies -blame)")
+	since := fs.String("since", "", "Only check annotations added or changed between this git ref and HEAD")
+	staged := fs.Bool("staged", false, "Only check the staged content of the files in the git index, for pre-commit hooks")
+	maxHorizon := fs.String("max-horizon", "0", "With -since, reject new deadlines further in the future than this, e.g. 180d")
+	baselineFile := fs.String("baseline", "", "Ignore the findings in this baseline file (default: "+deadline.BaselineFile+" in -dir, if it exists)")
+	fs.Parse(args)
+
+	horizon, err := deadline.ParseDuration(*maxHorizon)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -max-horizon: %v\n", err)
+		return exitUsage
+	}
+	scanner, err := sf.scanner(func(opts *deadline.Options) { opts.MaxHorizon = horizon })
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	report, ok := reporters[*format]
+	switch *groupBy {
+	case "":
+	case "author":
+		*blame = true
+		report, ok = authorReporters[*format]
+	default:
+		fmt.Fprintf(os.Stderr, "unknown -group-by: %s\n", *groupBy)
+		return exitUsage
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown format: %s\n", *format)
+		return exitUsage
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var findings []deadline.Finding
+	switch {
+	case *since != "" && *staged:
+		fmt.Fprintln(os.Stderr, "-since and -staged can't be combined")
+		return exitUsage
+	case *since != "":
+		findings, err = scanner.ScanChanges(ctx, sf.dir, *since)
+	case *staged:
+		findings, err = scanner.ScanStaged(ctx, sf.dir)
+		findings = withoutStatus(findings, deadline.StatusOK)
+	default:
+		findings, err = scanner.ScanDir(ctx, sf.dir)
+		findings = withoutStatus(findings, deadline.StatusOK)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+
+	baseline, err := loadBaseline(*baselineFile, sf.dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if baseline != nil {
+		var baselined int
+		var fixed []deadline.BaselineEntry
+		findings, baselined, fixed = baseline.Apply(findings)
+		if baselined > 0 {
+			fmt.Fprintf(os.Stderr, "%d findings are in the baseline\n", baselined)
+		}
+		// With -since and -staged only some files are scanned, so missing
+		// entries aren't necessarily fixed.
+		if *since == "" && !*staged {
+			for _, e := range fixed {
+				fmt.Fprintf(os.Stderr, "Baseline entry fixed: %s:%d %s (%s)\n", e.File, e.Line, e.Description, e.Fingerprint)
+			}
+			if len(fixed) > 0 {
+				fmt.Fprintln(os.Stderr, "run 'deadline baseline prune' to remove the fixed entries")
+			}
+		}
+	}
+
+	if *blame && len(findings) > 0 {
+		if err := deadline.BlameFindings(ctx, sf.dir, findings); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+
+	if err := report(os.Stdout, findings); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+
+	if hasStatus(findings, deadline.StatusRejected) {
+		fmt.Fprintln(os.Stderr, "at least one new deadline violates the policy")
+		return exitExpired
+	}
+	if failsCI(findings, deadline.StatusExpired) {
+		fmt.Fprintln(os.Stderr, "at least one deadline exceeded")
+		return exitExpired
+	}
+	if failsCI(findings, deadline.StatusExpiring) {
+		fmt.Fprintln(os.Stderr, "at least one deadline expiring soon")
+		return exitExpiring
+	}
+	return exitOK
+}
+
+// withoutStatus drops all findings with the given status.
+func withoutStatus(findings []deadline.Finding, status deadline.Status) []deadline.Finding {
+	var result []deadline.Finding
+	for _, f := range findings {
+		if f.Status != status {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// hasStatus reports whether any of the findings has the given status.
+func hasStatus(findings []deadline.Finding, status deadline.Status) bool {
+	for _, f := range findings {
+		if f.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// failsCI reports whether any of the findings with the given status
+// should fail the build.
+func failsCI(findings []deadline.Finding, status deadline.Status) bool {
+	for _, f := range findings {
+		if f.Status == status && f.Check.Severity == deadline.SeverityFailCI {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSize parses a number of bytes with an optional unit: KB, MB or GB
+// (powers of 1024).
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for i, unit := range []string{"KB", "MB", "GB"} {
+		if n, ok := strings.CutSuffix(s, unit); ok {
+			s, multiplier = n, 1<<(10*(i+1))
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSuffix(s, "B"), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * multiplier, nil
+}
+
+// stringList is a flag.Value collecting repeated or comma separated values.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	if len(*l) == 0 {
+		return fmt.Errorf("empty pattern")
+	}
+	return nil
+}
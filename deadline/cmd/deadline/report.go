@@ -0,0 +1,470 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+// reporters maps the values of the -format flag to the function writing
+// the findings in that format.
+var reporters = map[string]func(io.Writer, []deadline.Finding) error{
+	"text":       writeText,
+	"json":       writeJSON,
+	"sarif":      writeSARIF,
+	"junit":      writeJUnit,
+	"checkstyle": writeCheckstyle,
+}
+
+// authorReporters are used instead of reporters with -group-by author.
+var authorReporters = map[string]func(io.Writer, []deadline.Finding) error{
+	"text": writeTextByAuthor,
+	"json": writeJSONByAuthor,
+}
+
+const (
+	ruleExpired  = "deadline-exceeded"
+	ruleExpiring = "deadline-expiring"
+	ruleRejected = "deadline-rejected"
+)
+
+// ruleID returns the rule reported for a finding by the structured formats.
+func ruleID(f deadline.Finding) string {
+	switch f.Status {
+	case deadline.StatusExpiring:
+		return ruleExpiring
+	case deadline.StatusRejected:
+		return ruleRejected
+	default:
+		return ruleExpired
+	}
+}
+
+func writeText(w io.Writer, findings []deadline.Finding) error {
+	for _, f := range findings {
+		var prefix string
+		switch f.Check.Severity {
+		case deadline.SeverityWarn:
+			prefix = "Warning: deadline"
+		case deadline.SeverityInfo:
+			prefix = "Info: deadline"
+		default:
+			prefix = "Deadline"
+		}
+		state := "exceeded"
+		switch f.Status {
+		case deadline.StatusExpiring:
+			state = fmt.Sprintf("expiring in %d days", -f.DaysOverdue)
+		case deadline.StatusRejected:
+			prefix, state = "Deadline", "rejected ("+f.Reason+")"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s in file: %s, line: %d\nDEADLINE: %s\n", prefix, state, f.File, f.Line, f.Text); err != nil {
+			return err
+		}
+		if b := f.Blame; b != nil {
+			if _, err := fmt.Fprintf(w, "AUTHOR: %s <%s>, %s, commit %.8s\n", b.Author, b.Email, b.Time.Format("2006-01-02"), b.Commit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// authorGroup holds the findings blamed on a single author.
+type authorGroup struct {
+	Author   string
+	Email    string
+	Findings []deadline.Finding
+}
+
+// groupByAuthor groups the findings by the email address of the blamed
+// author. Authors with the most findings come first, findings which
+// couldn't be blamed are grouped under "unknown".
+func groupByAuthor(findings []deadline.Finding) []*authorGroup {
+	index := make(map[string]*authorGroup)
+	var groups []*authorGroup
+	for _, f := range findings {
+		author, email := "unknown", ""
+		if f.Blame != nil {
+			author, email = f.Blame.Author, f.Blame.Email
+		}
+		key := email
+		if key == "" {
+			key = author
+		}
+		g, ok := index[key]
+		if !ok {
+			g = &authorGroup{Author: author, Email: email}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.Findings = append(g.Findings, f)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Findings) != len(groups[j].Findings) {
+			return len(groups[i].Findings) > len(groups[j].Findings)
+		}
+		return groups[i].Author < groups[j].Author
+	})
+	return groups
+}
+
+func writeTextByAuthor(w io.Writer, findings []deadline.Finding) error {
+	for _, g := range groupByAuthor(findings) {
+		name := g.Author
+		if g.Email != "" {
+			name += " <" + g.Email + ">"
+		}
+		if _, err := fmt.Fprintf(w, "%s (%d)\n", name, len(g.Findings)); err != nil {
+			return err
+		}
+		for _, f := range g.Findings {
+			if _, err := fmt.Fprintf(w, "  %s:%d: %s\n", f.File, f.Line, message(f)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeJSONByAuthor(w io.Writer, findings []deadline.Finding) error {
+	type jsonAuthor struct {
+		Author   string        `json:"author"`
+		Email    string        `json:"email,omitempty"`
+		Findings []jsonFinding `json:"findings"`
+	}
+	out := struct {
+		Authors []jsonAuthor `json:"authors"`
+	}{Authors: []jsonAuthor{}}
+	for _, g := range groupByAuthor(findings) {
+		a := jsonAuthor{Author: g.Author, Email: g.Email}
+		for _, f := range g.Findings {
+			a.Findings = append(a.Findings, toJSONFinding(f))
+		}
+		out.Authors = append(out.Authors, a)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// message is the human readable summary of a finding used by the
+// structured formats.
+func message(f deadline.Finding) string {
+	date := formatDeadline(f.Check.Deadline)
+	msg := fmt.Sprintf("Deadline %s exceeded by %d days", date, f.DaysOverdue)
+	switch f.Status {
+	case deadline.StatusExpiring:
+		msg = fmt.Sprintf("Deadline %s expires in %d days", date, -f.DaysOverdue)
+	case deadline.StatusRejected:
+		msg = fmt.Sprintf("Deadline %s rejected: %s", date, f.Reason)
+	}
+	if f.Check.Description != "" {
+		msg += ": " + f.Check.Description
+	}
+	return msg
+}
+
+type jsonFinding struct {
+	File        string            `json:"file"`
+	Line        int               `json:"line"`
+	Column      int               `json:"column"`
+	Deadline    string            `json:"deadline"`
+	Status      deadline.Status   `json:"status"`
+	DaysOverdue int               `json:"days_overdue"`
+	Reason      string            `json:"reason,omitempty"`
+	Pattern     string            `json:"pattern"`
+	Severity    deadline.Severity `json:"severity"`
+	Owner       string            `json:"owner,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Text        string            `json:"text"`
+	Blame       *jsonBlame        `json:"blame,omitempty"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+type jsonBlame struct {
+	Commit string `json:"commit"`
+	Author string `json:"author"`
+	Email  string `json:"email"`
+	Time   string `json:"time"`
+}
+
+func toJSONFinding(f deadline.Finding) jsonFinding {
+	jf := jsonFinding{
+		File:        filepath.ToSlash(f.File),
+		Line:        f.Line,
+		Column:      f.Column,
+		Deadline:    formatDeadline(f.Check.Deadline),
+		Status:      f.Status,
+		DaysOverdue: f.DaysOverdue,
+		Reason:      f.Reason,
+		Pattern:     f.Check.Pattern,
+		Severity:    f.Check.Severity,
+		Owner:       f.Check.Owner,
+		Tags:        f.Check.Tags,
+		Description: f.Check.Description,
+		Text:        f.Text,
+		Fingerprint: f.Fingerprint,
+	}
+	if b := f.Blame; b != nil {
+		jf.Blame = &jsonBlame{
+			Commit: b.Commit,
+			Author: b.Author,
+			Email:  b.Email,
+			Time:   b.Time.Format(time.RFC3339),
+		}
+	}
+	return jf
+}
+
+func writeJSON(w io.Writer, findings []deadline.Finding) error {
+	out := struct {
+		Findings []jsonFinding `json:"findings"`
+	}{Findings: []jsonFinding{}}
+	for _, f := range findings {
+		out.Findings = append(out.Findings, toJSONFinding(f))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// SARIF 2.1.0, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties jsonFinding     `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+func sarifLevel(f deadline.Finding) string {
+	if f.Status == deadline.StatusRejected {
+		return "error"
+	}
+	switch f.Check.Severity {
+	case deadline.SeverityWarn:
+		return "warning"
+	case deadline.SeverityInfo:
+		return "note"
+	default:
+		if f.Status == deadline.StatusExpiring {
+			return "warning"
+		}
+		return "error"
+	}
+}
+
+func writeSARIF(w io.Writer, findings []deadline.Finding) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "deadline",
+			InformationURI: "https://github.com/SimonWaldherr/gotools/tree/master/deadline",
+			Rules: []sarifRule{{
+				ID:               ruleExpired,
+				ShortDescription: sarifMessage{Text: "The deadline of a @CHECK annotation has passed"},
+			}, {
+				ID:               ruleExpiring,
+				ShortDescription: sarifMessage{Text: "The deadline of a @CHECK annotation passes soon"},
+			}, {
+				ID:               ruleRejected,
+				ShortDescription: sarifMessage{Text: "A new @CHECK annotation violates the deadline policy"},
+			}},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, f := range findings {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  ruleID(f),
+			Level:   sarifLevel(f),
+			Message: sarifMessage{Text: message(f)},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.File)},
+				Region:           sarifRegion{StartLine: f.Line, StartColumn: f.Column},
+			}}},
+			Properties: toJSONFinding(f),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, findings []deadline.Finding) error {
+	suite := junitTestSuite{Name: "deadline", Tests: len(findings)}
+	for _, f := range findings {
+		tc := junitTestCase{
+			Name:      fmt.Sprintf("%s:%d", filepath.ToSlash(f.File), f.Line),
+			Classname: filepath.ToSlash(f.File),
+		}
+		if f.Status == deadline.StatusRejected || f.Status == deadline.StatusExpired && f.Check.Severity == deadline.SeverityFailCI {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: message(f), Type: ruleID(f), Body: f.Text}
+		} else {
+			tc.SystemOut = fmt.Sprintf("%s: %s\n%s", f.Check.Severity, message(f), f.Text)
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	return writeXML(w, junitTestSuites{Suites: []junitTestSuite{suite}})
+}
+
+type checkstyleResult struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func checkstyleSeverity(f deadline.Finding) string {
+	if f.Status == deadline.StatusRejected {
+		return "error"
+	}
+	switch f.Check.Severity {
+	case deadline.SeverityWarn:
+		return "warning"
+	case deadline.SeverityInfo:
+		return "info"
+	default:
+		if f.Status == deadline.StatusExpiring {
+			return "warning"
+		}
+		return "error"
+	}
+}
+
+func writeCheckstyle(w io.Writer, findings []deadline.Finding) error {
+	result := checkstyleResult{Version: "4.3"}
+	index := make(map[string]int)
+	for _, f := range findings {
+		name := filepath.ToSlash(f.File)
+		i, ok := index[name]
+		if !ok {
+			i = len(result.Files)
+			index[name] = i
+			result.Files = append(result.Files, checkstyleFile{Name: name})
+		}
+		result.Files[i].Errors = append(result.Files[i].Errors, checkstyleError{
+			Line:     f.Line,
+			Column:   f.Column,
+			Severity: checkstyleSeverity(f),
+			Message:  message(f),
+			Source:   "deadline." + ruleID(f),
+		})
+	}
+
+	return writeXML(w, result)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// uploader creates or updates the issues through the REST API of GitHub or
+// GitLab. The ticket of a finding is found by its fingerprint label.
+type uploader struct {
+	tracker string
+	api     string
+	repo    string
+	token   string
+	client  *http.Client
+}
+
+func newUploader(tracker, api, repo, token string) (*uploader, error) {
+	u := &uploader{tracker: tracker, api: api, repo: repo, token: token, client: &http.Client{Timeout: 30 * time.Second}}
+	switch tracker {
+	case "github":
+		if u.api == "" {
+			u.api = "https://api.github.com"
+		}
+		if u.token == "" {
+			u.token = os.Getenv("GITHUB_TOKEN")
+		}
+	case "gitlab":
+		if u.api == "" {
+			u.api = "https://gitlab.com/api/v4"
+		}
+		if u.token == "" {
+			u.token = os.Getenv("GITLAB_TOKEN")
+		}
+	default:
+		return nil, fmt.Errorf("-upload needs -to github or gitlab")
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("-upload needs -repo")
+	}
+	u.api = strings.TrimRight(u.api, "/")
+	return u, nil
+}
+
+// upload creates the issues which don't exist yet and updates the others.
+func (u *uploader) upload(ctx context.Context, issues []issue) (created, updated int, err error) {
+	for _, is := range issues {
+		id, err := u.find(ctx, fingerprintLabel(is.Fingerprint))
+		if err != nil {
+			return created, updated, err
+		}
+
+		var payload any = toGitHubIssue(is)
+		if u.tracker == "gitlab" {
+			payload = toGitLabIssue(is)
+		}
+		if id == 0 {
+			if err := u.do(ctx, http.MethodPost, u.issuesURL(), payload, nil); err != nil {
+				return created, updated, err
+			}
+			created++
+			continue
+		}
+
+		method := http.MethodPatch
+		if u.tracker == "gitlab" {
+			method = http.MethodPut
+		}
+		if err := u.do(ctx, method, fmt.Sprintf("%s/%d", u.issuesURL(), id), payload, nil); err != nil {
+			return created, updated, err
+		}
+		updated++
+	}
+	return created, updated, nil
+}
+
+func (u *uploader) issuesURL() string {
+	if u.tracker == "gitlab" {
+		return u.api + "/projects/" + url.PathEscape(u.repo) + "/issues"
+	}
+	return u.api + "/repos/" + u.repo + "/issues"
+}
+
+// find returns the number (GitHub) or IID (GitLab) of the issue with the
+// label, or 0 if there is none.
+func (u *uploader) find(ctx context.Context, label string) (int, error) {
+	q := url.Values{"labels": {label}, "per_page": {"1"}}
+	if u.tracker == "github" {
+		q.Set("state", "all")
+	}
+	var found []struct {
+		Number int `json:"number"`
+		IID    int `json:"iid"`
+	}
+	if err := u.do(ctx, http.MethodGet, u.issuesURL()+"?"+q.Encode(), nil, &found); err != nil {
+		return 0, err
+	}
+	if len(found) == 0 {
+		return 0, nil
+	}
+	if u.tracker == "gitlab" {
+		return found[0].IID, nil
+	}
+	return found[0].Number, nil
+}
+
+func (u *uploader) do(ctx context.Context, method, target string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if u.tracker == "github" {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if u.token != "" {
+			req.Header.Set("Authorization", "Bearer "+u.token)
+		}
+	} else if u.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", u.token)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, target, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/SimonWaldherr/gotools/deadline"
+)
+
+// runWatch keeps a live list of the expired and expiring annotations in
+// the terminal, rescanning the files as they change.
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	sf := addScanFlags(fs)
+	all := fs.Bool("all", false, "List all annotations, not only the expired and expiring ones")
+	fs.Parse(args)
+
+	scanner, err := sf.scanner(nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	err = scanner.Watch(ctx, sf.dir, func(findings []deadline.Finding) {
+		if !*all {
+			findings = withoutStatus(findings, deadline.StatusOK)
+		}
+		sort.SliceStable(findings, func(i, j int) bool {
+			return listSorters["date"](findings[i], findings[j])
+		})
+
+		expired, expiring := 0, 0
+		for _, f := range findings {
+			switch f.Status {
+			case deadline.StatusExpired:
+				expired++
+			case deadline.StatusExpiring:
+				expiring++
+			}
+		}
+		// Clear the screen and move the cursor home.
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("%d expired, %d expiring, updated %s (Ctrl-C to quit)\n\n", expired, expiring, time.Now().Format("15:04:05"))
+		if err := writeList(os.Stdout, findings); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, err)
+		return exitExpired
+	}
+	return exitOK
+}
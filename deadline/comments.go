@@ -0,0 +1,331 @@
+package deadline
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// language describes where comments and string literals start and end in
+// a file type, so annotations in code or strings can be ignored.
+type language struct {
+	name          string
+	lineComments  []string
+	blockComments []delimiter
+	strings       []delimiter
+	// hashWordStart only starts a # comment at the beginning of a word,
+	// like in shell scripts where a#b is a single word.
+	hashWordStart bool
+	// prose treats everything as comment except code blocks and spans.
+	prose bool
+	// text treats everything as comment.
+	text bool
+}
+
+// delimiter is the start and end of a block comment or string literal.
+// Delimiters sharing a prefix must be listed longest first, like """
+// before ".
+type delimiter struct {
+	open, close string
+	escape      bool
+	multiline   bool
+	// char only opens a literal which closes after a single character or
+	// escape sequence, so Rust lifetimes like 'a aren't taken as one.
+	char bool
+}
+
+var (
+	cStrings = []delimiter{
+		{open: `"`, close: `"`, escape: true},
+		{open: `'`, close: `'`, escape: true},
+	}
+	cBlock = []delimiter{{open: "/*", close: "*/", multiline: true}}
+
+	langGo = &language{
+		name:          "go",
+		lineComments:  []string{"//"},
+		blockComments: cBlock,
+		strings:       append([]delimiter{{open: "`", close: "`", multiline: true}}, cStrings...),
+	}
+	langC = &language{
+		name:          "c",
+		lineComments:  []string{"//"},
+		blockComments: cBlock,
+		strings:       cStrings,
+	}
+	langJS = &language{
+		name:          "javascript",
+		lineComments:  []string{"//"},
+		blockComments: cBlock,
+		strings:       append([]delimiter{{open: "`", close: "`", escape: true, multiline: true}}, cStrings...),
+	}
+	langRust = &language{
+		name:          "rust",
+		lineComments:  []string{"//"},
+		blockComments: cBlock,
+		strings: []delimiter{
+			{open: `r#"`, close: `"#`, multiline: true},
+			{open: `r"`, close: `"`, multiline: true},
+			{open: `"`, close: `"`, escape: true, multiline: true},
+			{open: `'`, close: `'`, escape: true, char: true},
+		},
+	}
+	langCSS = &language{
+		name:          "css",
+		blockComments: cBlock,
+		strings:       cStrings,
+	}
+	langPython = &language{
+		name:         "python",
+		lineComments: []string{"#"},
+		strings: append([]delimiter{
+			{open: `"""`, close: `"""`, escape: true, multiline: true},
+			{open: `'''`, close: `'''`, escape: true, multiline: true},
+		}, cStrings...),
+	}
+	langShell = &language{
+		name:          "shell",
+		lineComments:  []string{"#"},
+		hashWordStart: true,
+		strings: []delimiter{
+			{open: `"`, close: `"`, escape: true, multiline: true},
+			{open: `'`, close: `'`, multiline: true},
+		},
+	}
+	langSQL = &language{
+		name:          "sql",
+		lineComments:  []string{"--"},
+		blockComments: cBlock,
+		strings: []delimiter{
+			{open: `'`, close: `'`, multiline: true},
+			{open: `"`, close: `"`},
+		},
+	}
+	langYAML = &language{
+		name:          "yaml",
+		lineComments:  []string{"#"},
+		hashWordStart: true,
+		strings:       []delimiter{{open: `"`, close: `"`, escape: true}, {open: `'`, close: `'`}},
+	}
+	langINI = &language{
+		name:         "ini",
+		lineComments: []string{"#", ";"},
+		strings:      []delimiter{{open: `"`, close: `"`, escape: true}},
+	}
+	langLua = &language{
+		name:          "lua",
+		lineComments:  []string{"--"},
+		blockComments: []delimiter{{open: "--[[", close: "]]", multiline: true}},
+		strings:       cStrings,
+	}
+	langXML = &language{
+		name:          "xml",
+		blockComments: []delimiter{{open: "<!--", close: "-->", multiline: true}},
+	}
+	langMarkdown = &language{name: "markdown", prose: true}
+	langText     = &language{name: "text", text: true}
+)
+
+// languages maps file extensions (and some well known file names) to their
+// language.
+var languages = map[string]*language{
+	".go": langGo,
+
+	".c": langC, ".h": langC, ".cc": langC, ".cpp": langC, ".cxx": langC, ".hpp": langC,
+	".java": langC, ".cs": langC, ".kt": langC, ".kts": langC, ".scala": langC,
+	".swift": langC, ".dart": langC, ".proto": langC, ".groovy": langC,
+	".gradle": langC, ".m": langC, ".php": langC,
+
+	".rs": langRust,
+
+	".js": langJS, ".jsx": langJS, ".mjs": langJS, ".cjs": langJS, ".ts": langJS, ".tsx": langJS,
+
+	".css": langCSS, ".scss": langC, ".less": langC,
+
+	".py": langPython, ".pyi": langPython,
+
+	".sh": langShell, ".bash": langShell, ".zsh": langShell, ".ksh": langShell,
+	".rb": langShell, ".pl": langShell, ".r": langShell, ".tf": langShell,
+	"Makefile": langShell, "Dockerfile": langShell, ".mk": langShell, ".cmake": langShell,
+
+	".sql": langSQL,
+
+	".yaml": langYAML, ".yml": langYAML, ".toml": langINI, ".ini": langINI, ".cfg": langINI,
+	".conf": langINI, ".properties": langINI,
+
+	".lua": langLua,
+
+	".html": langXML, ".htm": langXML, ".xml": langXML, ".svg": langXML, ".vue": langXML,
+
+	".md": langMarkdown, ".markdown": langMarkdown,
+
+	".txt": langText, ".rst": langText, ".adoc": langText, ".log": langText,
+}
+
+// languageFor returns the language of a file or nil if it is unknown.
+func languageFor(filename string) *language {
+	base := filepath.Base(filename)
+	if lang, ok := languages[base]; ok {
+		return lang
+	}
+	return languages[strings.ToLower(filepath.Ext(base))]
+}
+
+// commentMasker blanks out everything but comments, line by line. The
+// masked line has the same length as the original, so the offsets of
+// matches stay correct. It keeps block comments and multiline strings
+// open across lines.
+type commentMasker struct {
+	lang    *language
+	block   *delimiter
+	str     *delimiter
+	inFence string
+}
+
+func newCommentMasker(lang *language) *commentMasker {
+	return &commentMasker{lang: lang}
+}
+
+func (m *commentMasker) mask(line string) string {
+	switch {
+	case m.lang.text:
+		return line
+	case m.lang.prose:
+		return m.maskProse(line)
+	}
+
+	out := []byte(strings.Repeat(" ", len(line)))
+	i := 0
+	for i < len(line) {
+		if m.block != nil {
+			end := strings.Index(line[i:], m.block.close)
+			if end < 0 {
+				copy(out[i:], line[i:])
+				return string(out)
+			}
+			end += i + len(m.block.close)
+			copy(out[i:end], line[i:end])
+			i, m.block = end, nil
+			continue
+		}
+
+		if m.str != nil {
+			i = m.skipString(line, i)
+			continue
+		}
+
+		if d := m.startsWith(line, i, m.lang.blockComments); d != nil {
+			m.block = d
+			copy(out[i:], d.open)
+			i += len(d.open)
+			continue
+		}
+		if m.lineComment(line, i) {
+			copy(out[i:], line[i:])
+			return string(out)
+		}
+		if d := m.startsWith(line, i, m.lang.strings); d != nil {
+			m.str = d
+			i += len(d.open)
+			continue
+		}
+		i++
+	}
+
+	// Unterminated single line strings end with the line.
+	if m.str != nil && !m.str.multiline {
+		m.str = nil
+	}
+	return string(out)
+}
+
+// skipString advances past the content of the current string literal.
+func (m *commentMasker) skipString(line string, i int) int {
+	for i < len(line) {
+		if m.str.escape && line[i] == '\\' {
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(line[i:], m.str.close) {
+			i += len(m.str.close)
+			m.str = nil
+			return i
+		}
+		i++
+	}
+	return len(line)
+}
+
+func (m *commentMasker) lineComment(line string, i int) bool {
+	for _, c := range m.lang.lineComments {
+		if !strings.HasPrefix(line[i:], c) {
+			continue
+		}
+		if m.lang.hashWordStart && c == "#" && i > 0 && line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (m *commentMasker) startsWith(line string, i int, delims []delimiter) *delimiter {
+	for j := range delims {
+		d := &delims[j]
+		if !strings.HasPrefix(line[i:], d.open) {
+			continue
+		}
+		if d.char && !closesAfterChar(line[i+len(d.open):], d.close) {
+			continue
+		}
+		return d
+	}
+	return nil
+}
+
+// closesAfterChar reports whether s starts with a single character or an
+// escape sequence like \n or \u{1F600} followed by close.
+func closesAfterChar(s, close string) bool {
+	if strings.HasPrefix(s, `\`) {
+		end := strings.Index(s[min(len(s), 2):], close)
+		return end >= 0 && end+2 <= len(`\u{10FFFF}`)
+	}
+	_, n := utf8.DecodeRuneInString(s)
+	return n > 0 && strings.HasPrefix(s[n:], close)
+}
+
+// maskProse blanks out fenced code blocks and inline code spans of
+// Markdown files.
+func (m *commentMasker) maskProse(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) < 4 {
+		for _, fence := range []string{"```", "~~~"} {
+			if strings.HasPrefix(trimmed, fence) && (m.inFence == "" || m.inFence == fence) {
+				if m.inFence == "" {
+					m.inFence = fence
+				} else {
+					m.inFence = ""
+				}
+				return strings.Repeat(" ", len(line))
+			}
+		}
+	}
+	if m.inFence != "" {
+		return strings.Repeat(" ", len(line))
+	}
+
+	out := []byte(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] != '`' {
+			continue
+		}
+		end := strings.IndexByte(line[i+1:], '`')
+		if end < 0 {
+			break
+		}
+		end += i + 2
+		copy(out[i:end], strings.Repeat(" ", end-i))
+		i = end - 1
+	}
+	return string(out)
+}
@@ -0,0 +1,49 @@
+package deadline
+
+import "testing"
+
+func TestCommentMasking(t *testing.T) {
+	const a = "@CHECK(2025-03-01;;;; x)"
+	tests := []struct {
+		name, file, content string
+		want                int
+	}{
+		{"go line comment", "main.go", "x := 1 // " + a + "\n", 1},
+		{"go string", "main.go", "s := \"// " + a + "\"\n", 0},
+		{"go raw string across lines", "main.go", "s := `\n// " + a + "\n`\n", 0},
+		{"go block comment across lines", "main.go", "/*\n" + a + "\n*/\n", 1},
+		{"code without comment", "main.go", "f(\"x\", " + a + ")\n", 0},
+		{"escaped quote", "main.c", "s = \"\\\" // " + a + "\";\n", 0},
+		{"python docstring", "x.py", "\"\"\"\n# " + a + "\n\"\"\"\n", 0},
+		{"python comment", "x.py", "x = 'a' # " + a + "\n", 1},
+		{"shell word with hash", "x.sh", "echo a#" + a + "\n", 0},
+		{"shell comment", "x.sh", "echo a # " + a + "\n", 1},
+		{"sql comment", "x.sql", "SELECT '--' -- " + a + "\n", 1},
+		{"rust lifetime", "x.rs", "fn f<'a>(x: &'a str) -> &'static str { x } // " + a + "\n", 1},
+		{"rust char literals", "x.rs", "let c = ['\"', '\\'', '\\u{1F600}']; // " + a + "\n", 1},
+		{"rust raw string", "x.rs", "let s = r#\"// \"" + a + "\"#;\n", 0},
+		{"html comment", "x.html", "<p>" + a + "</p><!-- " + a + " -->\n", 1},
+		{"markdown code span", "x.md", "`" + a + "` " + a + "\n", 1},
+		{"markdown fence", "x.md", "```\n" + a + "\n```\n", 0},
+		{"text", "notes.txt", a + "\n", 1},
+		{"unknown type", "x.unknown", "# " + a + "\n", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(scanString(t, Options{}, tt.file, tt.content)); got != tt.want {
+				t.Errorf("got %d findings in %q, want %d", got, tt.content, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskKeepsOffsets(t *testing.T) {
+	line := `s := "ä" // ö`
+	masked := newCommentMasker(langGo).mask(line)
+	if len(masked) != len(line) {
+		t.Fatalf("masked line has %d bytes, want %d", len(masked), len(line))
+	}
+	if want := `         // ö`; masked[len(masked)-len(want):] != want {
+		t.Errorf("got %q", masked)
+	}
+}
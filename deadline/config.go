@@ -0,0 +1,339 @@
+package deadline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFiles are looked up in the scanned directory if no config file is
+// given.
+var configFiles = []string{".deadline.yaml", ".deadline.yml", ".deadline.json"}
+
+// Config is the content of a .deadline.yaml or .deadline.json file.
+// Timezone is the IANA zone of dates written without one (default UTC).
+// Calendar is the path of a calendar file, relative to the config file.
+// NextWorkingDay moves deadlines on weekends and holidays to the next
+// working day.
+type Config struct {
+	Patterns       []PatternConfig  `yaml:"patterns" json:"patterns"`
+	Overrides      []OverrideConfig `yaml:"overrides" json:"overrides"`
+	Timezone       string           `yaml:"timezone" json:"timezone"`
+	Calendar       string           `yaml:"calendar" json:"calendar"`
+	NextWorkingDay bool             `yaml:"next_working_day" json:"next_working_day"`
+}
+
+// PatternConfig defines a named annotation syntax. The fields date,
+// severity, owner, tags and description are taken from the named groups of
+// Regex, Fields maps a field to a different group name or index.
+// DateFormats are Go time layouts or "iso-week" for dates like 2025-W05-3.
+type PatternConfig struct {
+	Name        string            `yaml:"name" json:"name"`
+	Regex       string            `yaml:"regex" json:"regex"`
+	Fields      map[string]string `yaml:"fields" json:"fields"`
+	DateFormats []string          `yaml:"date_formats" json:"date_formats"`
+	Severity    string            `yaml:"severity" json:"severity"`
+}
+
+// OverrideConfig changes the behavior for all files matching Paths (in
+// .gitignore syntax). A non-empty Severity replaces the severity of all
+// annotations, a non-empty Patterns restricts the patterns to the named ones.
+type OverrideConfig struct {
+	Paths    []string `yaml:"paths" json:"paths"`
+	Severity string   `yaml:"severity" json:"severity"`
+	Patterns []string `yaml:"patterns" json:"patterns"`
+}
+
+// defaultPattern is the built-in @CHECK syntax. Its date is a day, a day
+// with a time of day and optional zone, or a relative date from the
+// calendar. A configured pattern with the same name replaces it.
+var defaultPattern = PatternConfig{
+	Name:        "check",
+	Regex:       `@CHECK\((?P<date>\d{4}-\d{2}-\d{2}(?:T\d{2}:\d{2}(?::\d{2})?(?:Z|[+-]\d{2}:\d{2})?)?|(?i:(?:end of )?sprint|release) [^;()]+);(?P<severity>[^;]*);(?P<owner>[^;]*);(?P<tags>[^;]*);(?P<description>[^;]*)\)`,
+	DateFormats: absoluteFormats,
+	Severity:    string(SeverityFailCI),
+}
+
+var fieldNames = []string{"date", "severity", "owner", "tags", "description"}
+
+// pattern is a compiled PatternConfig.
+type pattern struct {
+	name        string
+	re          *regexp.Regexp
+	groups      map[string]int
+	dateFormats []string
+	severity    Severity
+}
+
+type override struct {
+	paths    ignoreList
+	severity Severity
+	patterns map[string]bool
+}
+
+// config is a compiled Config.
+type config struct {
+	patterns  []*pattern
+	overrides []override
+	loc       *time.Location
+}
+
+// LoadConfig reads the config file at path. If path is empty, the default
+// config files are looked up in dir. Without a config file the returned
+// Config is empty, so only the built-in @CHECK pattern is used.
+func LoadConfig(path, dir string) (*Config, error) {
+	var cfg Config
+	if path == "" {
+		for _, name := range configFiles {
+			candidate := filepath.Join(dir, name)
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Ext(path) == ".json" {
+			err = json.Unmarshal(data, &cfg)
+		} else {
+			err = yaml.Unmarshal(data, &cfg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if cfg.Calendar != "" && !filepath.IsAbs(cfg.Calendar) {
+			cfg.Calendar = filepath.Join(filepath.Dir(path), cfg.Calendar)
+		}
+	}
+
+	if _, err := compileConfig(cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &cfg, nil
+}
+
+func compileConfig(cfg Config) (*config, error) {
+	c := &config{loc: time.UTC}
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", cfg.Timezone)
+		}
+		c.loc = loc
+	}
+	configs := []PatternConfig{defaultPattern}
+	for _, pc := range cfg.Patterns {
+		if pc.Name == defaultPattern.Name {
+			configs[0] = pc
+			continue
+		}
+		configs = append(configs, pc)
+	}
+
+	names := make(map[string]bool)
+	for _, pc := range configs {
+		if names[pc.Name] {
+			return nil, fmt.Errorf("duplicate pattern %q", pc.Name)
+		}
+		names[pc.Name] = true
+
+		p, err := compilePattern(pc)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %v", pc.Name, err)
+		}
+		c.patterns = append(c.patterns, p)
+	}
+
+	for i, oc := range cfg.Overrides {
+		o := override{paths: parseIgnorePatterns(oc.Paths, "")}
+		if oc.Severity != "" {
+			severity, err := ParseSeverity(oc.Severity)
+			if err != nil {
+				return nil, fmt.Errorf("override %d: %v", i+1, err)
+			}
+			o.severity = severity
+		}
+		if len(oc.Patterns) > 0 {
+			o.patterns = make(map[string]bool)
+			for _, name := range oc.Patterns {
+				if !names[name] {
+					return nil, fmt.Errorf("override %d: unknown pattern %q", i+1, name)
+				}
+				o.patterns[name] = true
+			}
+		}
+		c.overrides = append(c.overrides, o)
+	}
+	return c, nil
+}
+
+func compilePattern(pc PatternConfig) (*pattern, error) {
+	if pc.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	re, err := regexp.Compile(pc.Regex)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pattern{
+		name:        pc.Name,
+		re:          re,
+		groups:      make(map[string]int),
+		dateFormats: pc.DateFormats,
+	}
+	if len(p.dateFormats) == 0 {
+		p.dateFormats = defaultPattern.DateFormats
+	}
+	if p.severity, err = ParseSeverity(pc.Severity); err != nil {
+		return nil, err
+	}
+
+	for _, field := range fieldNames {
+		group := field
+		if g, ok := pc.Fields[field]; ok {
+			group = g
+		}
+		if i, err := strconv.Atoi(group); err == nil {
+			if i < 1 || i > re.NumSubexp() {
+				return nil, fmt.Errorf("field %s: no group %d", field, i)
+			}
+			p.groups[field] = i
+		} else if i := re.SubexpIndex(group); i > 0 {
+			p.groups[field] = i
+		} else if _, ok := pc.Fields[field]; ok {
+			return nil, fmt.Errorf("field %s: no group %q", field, group)
+		}
+	}
+	for field := range pc.Fields {
+		if _, ok := p.groups[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+	}
+	if _, ok := p.groups["date"]; !ok {
+		return nil, fmt.Errorf("no date group")
+	}
+	return p, nil
+}
+
+// forFile returns the patterns which apply to the file with the slash
+// separated path rel and the severity overriding the annotations, if any.
+func (c *config) forFile(rel string) ([]*pattern, Severity) {
+	patterns := c.patterns
+	var severity Severity
+	for _, o := range c.overrides {
+		if !o.paths.matchPath(rel) {
+			continue
+		}
+		if o.severity != "" {
+			severity = o.severity
+		}
+		if o.patterns != nil {
+			patterns = nil
+			for _, p := range c.patterns {
+				if o.patterns[p.name] {
+					patterns = append(patterns, p)
+				}
+			}
+		}
+	}
+	return patterns, severity
+}
+
+// field returns the trimmed value of a field from the submatches of p.
+func (p *pattern) field(matches []string, name string) string {
+	if i, ok := p.groups[name]; ok {
+		return strings.TrimSpace(matches[i])
+	}
+	return ""
+}
+
+// parseCheck builds a Check from the submatches of p. An unknown severity
+// is reported as an error, but the returned check still fails CI so a
+// typo can't silently disable it.
+func (p *pattern) parseCheck(matches []string, dates *dateResolver) (Check, error) {
+	deadline, err := dates.parse(p, p.field(matches, "date"))
+	if err != nil {
+		return Check{}, err
+	}
+
+	check := Check{
+		Pattern:     p.name,
+		Deadline:    deadline,
+		Severity:    p.severity,
+		Owner:       p.field(matches, "owner"),
+		Description: p.field(matches, "description"),
+	}
+	for _, tag := range strings.Split(p.field(matches, "tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			check.Tags = append(check.Tags, tag)
+		}
+	}
+
+	if s := p.field(matches, "severity"); s != "" {
+		check.Severity, err = ParseSeverity(s)
+	}
+	return check, err
+}
+
+// formatDate formats t in the layout the original date was written in.
+// Relative dates are replaced by an absolute one.
+func (p *pattern) formatDate(t time.Time, original string) string {
+	for _, layout := range p.dateFormats {
+		if layout != "iso-week" {
+			if _, err := time.Parse(layout, original); err == nil {
+				return t.Format(layout)
+			}
+			continue
+		}
+		if m := isoWeekRegex.FindStringSubmatch(original); m != nil {
+			year, week := t.ISOWeek()
+			if m[3] == "" {
+				return fmt.Sprintf("%04d-W%02d", year, week)
+			}
+			return fmt.Sprintf("%04d-W%02d-%d", year, week, (int(t.Weekday())+6)%7+1)
+		}
+	}
+	if h, m, s := t.Clock(); h != 0 || m != 0 || s != 0 {
+		return t.Format("2006-01-02T15:04Z07:00")
+	}
+	return t.Format("2006-01-02")
+}
+
+var isoWeekRegex = regexp.MustCompile(`^(\d{4})-?W(\d{2})(?:-?([1-7]))?$`)
+
+// parseISOWeek parses ISO 8601 week dates like 2025-W05 or 2025-W05-3. A
+// date without a weekday refers to the Monday of that week.
+func parseISOWeek(s string) (time.Time, error) {
+	m := isoWeekRegex.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("invalid ISO week date %q", s)
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	weekday := 1
+	if m[3] != "" {
+		weekday, _ = strconv.Atoi(m[3])
+	}
+
+	// January 4th is always in week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7)
+	t := monday.AddDate(0, 0, (week-1)*7+weekday-1)
+	if y, w := t.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid ISO week date %q", s)
+	}
+	return t, nil
+}
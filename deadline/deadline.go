@@ -0,0 +1,144 @@
+// Package deadline finds annotations with a deadline in source code and
+// tells which of them have expired. An annotation looks like this (the
+// example is written so it doesn't match the annotation pattern itself):
+//
+//	@CHECK (2023-06-01;FAIL_CI;;refactoring; Improve database query performance)
+//
+//	s, err := deadline.NewScanner(deadline.Options{WarnWithin: 14 * 24 * time.Hour})
+//	if err != nil {
+//		return err
+//	}
+//	findings, err := s.ScanDir(ctx, ".")
+//
+// The command line tool is in the cmd/deadline directory.
+package deadline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Severity decides what happens once the deadline of a check has passed.
+type Severity string
+
+const (
+	// SeverityFailCI fails the build.
+	SeverityFailCI Severity = "FAIL_CI"
+	// SeverityWarn only prints a warning.
+	SeverityWarn Severity = "WARN"
+	// SeverityInfo only reports the check.
+	SeverityInfo Severity = "INFO"
+)
+
+// Check holds the five fields of a @CHECK annotation:
+// @CHECK(<date>;<severity>;<owner>;<tags>;<description>)
+// Pattern is the name of the configured pattern which matched.
+type Check struct {
+	Pattern     string
+	Deadline    time.Time
+	Severity    Severity
+	Owner       string
+	Tags        []string
+	Description string
+}
+
+// Status tells whether the deadline of a finding has already passed or
+// will pass soon.
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusExpired  Status = "expired"
+	StatusExpiring Status = "expiring"
+	// StatusRejected marks annotations found by Scanner.ScanChanges which
+	// violate the date policy.
+	StatusRejected Status = "rejected"
+)
+
+// Finding is an annotation found in a file. DaysOverdue is negative for
+// deadlines which haven't passed yet. Reason explains why a finding was
+// rejected. Fingerprint identifies the annotation across runs, see
+// Fingerprint.
+type Finding struct {
+	File        string
+	Line        int
+	Column      int
+	Text        string
+	Check       Check
+	Status      Status
+	DaysOverdue int
+	Reason      string
+	Blame       *Blame
+	Fingerprint string
+
+	// pattern and match (the submatch offsets within Text) allow to
+	// rewrite the annotation.
+	pattern *pattern
+	match   []int
+}
+
+// ParseSeverity maps the severity field of an annotation to a Severity.
+// An empty field defaults to SeverityFailCI, so does an unknown one, but
+// then an error is returned as well.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToUpper(strings.TrimSpace(s))); sev {
+	case "", SeverityFailCI:
+		return SeverityFailCI, nil
+	case SeverityWarn, SeverityInfo:
+		return sev, nil
+	default:
+		return SeverityFailCI, fmt.Errorf("invalid severity %q", strings.TrimSpace(s))
+	}
+}
+
+// ParseDuration extends time.ParseDuration with the units d (days) and
+// w (weeks), e.g. "14d" or "2w".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+
+	for unit, d := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, unit); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(v * float64(d)), nil
+		}
+	}
+	return time.ParseDuration(s)
+}
+
+// FormatDuration prints d in days if it is a whole number of days.
+func FormatDuration(d time.Duration) string {
+	if day := 24 * time.Hour; d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
+
+// postponedNote matches the note Finding.Bump adds to a description.
+var postponedNote = regexp.MustCompile(`\s*\(postponed by [^()]*\)\s*$`)
+
+// Fingerprint identifies an annotation by the slash separated path of its
+// file relative to the scanned directory and its pattern, owner, tags and
+// description. The line, the date, the severity and the note added by
+// bumping the deadline are left out, so the fingerprint survives code
+// moving around and deadlines being changed.
+// The scanner appends -2, -3, … to the fingerprints of identical
+// annotations in the same file.
+func Fingerprint(rel string, c Check) string {
+	h := sha256.New()
+	for _, s := range []string{rel, c.Pattern, c.Owner, strings.Join(c.Tags, ","), postponedNote.ReplaceAllString(c.Description, "")} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,82 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Severity
+		wantErr bool
+	}{
+		{"", SeverityFailCI, false},
+		{"fail_ci", SeverityFailCI, false},
+		{" WARN ", SeverityWarn, false},
+		{"info", SeverityInfo, false},
+		{"urgent", SeverityFailCI, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSeverity(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseSeverity(%q) = %s, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"14d", 14 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseDuration("xd"); err == nil {
+		t.Error("ParseDuration(\"xd\") didn't fail")
+	}
+}
+
+func TestFingerprintStableAcrossBump(t *testing.T) {
+	tests := []struct {
+		name, line string
+	}{
+		{"description", "// @CHECK(2025-03-01;;bob;db; Improve the queries)"},
+		{"empty description", "// @CHECK(2025-03-01;;bob;db;)"},
+		{"description with parentheses", "// @CHECK(2025-03-01;;bob;db; Drop the old API [see #12])"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scanString(t, Options{}, "main.go", tt.line+"\n")
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1", len(findings))
+			}
+			want := findings[0].Fingerprint
+
+			line := tt.line
+			for _, note := range []string{"postponed by 30d: waiting (upstream)", "postponed by 2w"} {
+				line = findings[0].Bump(line, 30*24*time.Hour, note)
+				findings = scanString(t, Options{}, "main.go", line+"\n")
+				if len(findings) != 1 {
+					t.Fatalf("got %d findings in %q, want 1", len(findings), line)
+				}
+				if got := findings[0].Fingerprint; got != want {
+					t.Errorf("fingerprint of %q is %s, want %s", line, got, want)
+				}
+			}
+			if got := findings[0].Check.Deadline; !got.Equal(time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("deadline of %q is %s, want 2025-04-30", line, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package deadline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// changedLines returns the lines added or changed between the merge base
+// of ref and HEAD, keyed by file path (joined with dir) and line number.
+func changedLines(ctx context.Context, dir, ref string) (map[string]map[int]bool, error) {
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "diff", "--unified=0", "--no-color", "--no-ext-diff", "--relative", "--src-prefix=a/", "--dst-prefix=b/", ref+"...HEAD")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s...HEAD: %v: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+	return parseDiff(dir, out), nil
+}
+
+// parseDiff collects the added lines from the hunk headers of a unified
+// diff with zero context lines.
+func parseDiff(dir string, out []byte) map[string]map[int]bool {
+	changed := make(map[string]map[int]bool)
+	var lines map[int]bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			// Names containing spaces are followed by a tab.
+			name, _, _ := strings.Cut(strings.TrimPrefix(line, "+++ "), "\t")
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+			if name == "/dev/null" {
+				lines = nil
+				continue
+			}
+			file := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(name, "b/")))
+			lines = make(map[int]bool)
+			changed[file] = lines
+		case strings.HasPrefix(line, "@@ ") && lines != nil:
+			// @@ -<old>[,<n>] +<start>[,<count>] @@
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			start, count := parseRange(strings.TrimPrefix(fields[2], "+"))
+			for i := start; i < start+count; i++ {
+				lines[i] = true
+			}
+		}
+	}
+	return changed
+}
+
+func parseRange(s string) (start, count int) {
+	first, n, found := strings.Cut(s, ",")
+	start, _ = strconv.Atoi(first)
+	count = 1
+	if found {
+		count, _ = strconv.Atoi(n)
+	}
+	return start, count
+}
+
+func sortedKeys(m map[string]map[int]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stagedFiles returns the slash separated paths, relative to dir, of the
+// files added, copied, modified or renamed in the git index.
+func stagedFiles(ctx context.Context, dir string) ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "diff", "--cached", "--name-only", "-z", "--diff-filter=ACMR", "--relative")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff --cached: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	var files []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// stagedContent returns the content of a file in the git index.
+func stagedContent(ctx context.Context, dir, name string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "show", ":./"+name)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show :%s: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,177 @@
+package deadline
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFiles are read from every directory while walking the tree. Their
+// patterns apply to the directory they live in and everything below.
+var ignoreFiles = []string{".gitignore", ".deadlineignore"}
+
+// defaultExcludes are skipped unless -no-default-excludes is given.
+var defaultExcludes = []string{".git/", ".hg/", ".svn/", "node_modules/", "vendor/"}
+
+// ignorePattern is a single pattern in .gitignore syntax.
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	base    string
+}
+
+// ignoreList is a list of patterns where the last matching pattern wins,
+// just like in a .gitignore file.
+type ignoreList []ignorePattern
+
+// match reports whether the slash separated path rel (relative to the root
+// of the walk) is matched by the list.
+func (l ignoreList) match(rel string, isDir bool) bool {
+	matched := false
+	for _, p := range l {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		name := rel
+		if p.base != "" {
+			if !strings.HasPrefix(rel, p.base+"/") {
+				continue
+			}
+			name = rel[len(p.base)+1:]
+		}
+		if p.re.MatchString(name) {
+			matched = !p.negate
+		}
+	}
+	return matched
+}
+
+// matchPath reports whether the file with the slash separated path rel or
+// one of its parent directories is matched by the list.
+func (l ignoreList) matchPath(rel string) bool {
+	for i := 0; i < len(rel); i++ {
+		if rel[i] == '/' && l.match(rel[:i], true) {
+			return true
+		}
+	}
+	return l.match(rel, false)
+}
+
+// parseIgnorePatterns compiles the given patterns relative to base.
+// Blank lines and comments are skipped.
+func parseIgnorePatterns(lines []string, base string) ignoreList {
+	var l ignoreList
+	for _, line := range lines {
+		if p, ok := parseIgnorePattern(line, base); ok {
+			l = append(l, p)
+		}
+	}
+	return l
+}
+
+func parseIgnorePattern(line, base string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	p := ignorePattern{base: base}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignorePattern{}, false
+	}
+
+	// A pattern containing a slash is anchored to base, otherwise it
+	// matches a name at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	prefix := "^(?:.*/)?"
+	if anchored {
+		prefix = "^"
+	}
+	re, err := regexp.Compile(prefix + globToRegex(line) + "$")
+	if err != nil {
+		return ignorePattern{}, false
+	}
+	p.re = re
+	return p, true
+}
+
+// globToRegex translates a glob with gitignore semantics to a regular
+// expression: * and ? don't match a slash, ** matches across directories.
+func globToRegex(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**") {
+				switch {
+				case strings.HasPrefix(glob[i:], "**/"):
+					b.WriteString("(?:.*/)?")
+					i += 2
+				default:
+					b.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// readIgnoreFiles loads the ignore files of the directory dir, whose slash
+// separated path relative to the root of the walk is rel.
+func readIgnoreFiles(dir, rel string) ignoreList {
+	var l ignoreList
+	for _, name := range ignoreFiles {
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var lines []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		file.Close()
+		l = append(l, parseIgnorePatterns(lines, rel)...)
+	}
+	return l
+}
@@ -0,0 +1,48 @@
+package deadline
+
+import "testing"
+
+func TestIgnoreList(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		base     string
+		rel      string
+		isDir    bool
+		want     bool
+	}{
+		{[]string{"*.log"}, "", "a/b/x.log", false, true},
+		{[]string{"*.log"}, "", "a/b/x.go", false, false},
+		{[]string{"/build"}, "", "build", true, true},
+		{[]string{"/build"}, "", "a/build", true, false},
+		{[]string{"build/"}, "", "a/build", true, true},
+		{[]string{"build/"}, "", "a/build", false, false},
+		{[]string{"docs/*.md"}, "", "docs/a.md", false, true},
+		{[]string{"docs/*.md"}, "", "docs/x/a.md", false, false},
+		{[]string{"docs/**/*.md"}, "", "docs/x/y/a.md", false, true},
+		{[]string{"**/gen"}, "", "a/b/gen", true, true},
+		{[]string{"x?.go"}, "", "x1.go", false, true},
+		{[]string{"x?.go"}, "", "x/.go", false, false},
+		{[]string{"*.go", "!keep.go"}, "", "keep.go", false, false},
+		{[]string{"!keep.go", "*.go"}, "", "keep.go", false, true},
+		{[]string{"# comment", "", `\#x`}, "", "#x", false, true},
+		{[]string{"*.tmp"}, "sub", "sub/a.tmp", false, true},
+		{[]string{"*.tmp"}, "sub", "other/a.tmp", false, false},
+		{[]string{"/a.tmp"}, "sub", "sub/a.tmp", false, true},
+	}
+	for _, tt := range tests {
+		l := parseIgnorePatterns(tt.patterns, tt.base)
+		if got := l.match(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("%q in %q: match(%q, %v) = %v, want %v", tt.patterns, tt.base, tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreListMatchPath(t *testing.T) {
+	l := parseIgnorePatterns([]string{"vendor/"}, "")
+	if !l.matchPath("a/vendor/x.go") {
+		t.Error("file in an ignored directory isn't matched")
+	}
+	if l.matchPath("a/vendor.go") {
+		t.Error("file named like an ignored directory is matched")
+	}
+}
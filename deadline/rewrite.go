@@ -0,0 +1,83 @@
+package deadline
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bump moves the deadline of the annotation f in line by d and appends
+// note to its description, if the pattern has one. The "(postponed by …)"
+// note of an earlier bump is replaced. line must be f.Text or
+// a version of it in which only annotations to the right of f changed.
+func (f Finding) Bump(line string, d time.Duration, note string) string {
+	p := f.pattern
+	if p == nil {
+		return line
+	}
+	i, ok := p.groups["date"]
+	if !ok || f.match[2*i] < 0 {
+		return line
+	}
+	oldDate := line[f.match[2*i]:f.match[2*i+1]]
+	newDate := p.formatDate(addDuration(f.Check.Deadline, d), oldDate)
+
+	var replacements []replacement
+	replacements = append(replacements, replacement{f.match[2*i], f.match[2*i+1], newDate})
+	if j, ok := p.groups["description"]; ok && f.match[2*j] >= 0 {
+		start, end := f.match[2*j], f.match[2*j+1]
+		desc := line[start:end]
+		// The description must not end the annotation early.
+		note = strings.NewReplacer(";", ",", "(", "[", ")", "]").Replace(note)
+		// A note of an earlier bump is replaced rather than stacked.
+		desc = postponedNote.ReplaceAllString(desc, "")
+		desc = strings.TrimRight(desc, " ") + " (" + note + ")"
+		replacements = append(replacements, replacement{start, end, desc})
+	}
+	return applyReplacements(line, replacements)
+}
+
+// emptyTrailingComment matches a comment left empty at the end of a line
+// after its annotation was removed.
+var emptyTrailingComment = regexp.MustCompile(`\s+(?://|#|--|/\*\s*\*/|<!--\s*-->)\s*$`)
+
+// Remove removes the annotation f from line. If nothing but a comment
+// marker remains, it reports that the whole line should be deleted. An
+// empty comment after code is removed as well.
+func (f Finding) Remove(line string) (string, bool) {
+	if f.match == nil {
+		return line, false
+	}
+	start, end := f.match[0], f.match[1]
+	rest := strings.TrimRight(line[:start], " \t") + line[end:]
+	if strings.Trim(rest, " \t/*#-;<!>") == "" {
+		return "", true
+	}
+	rest = emptyTrailingComment.ReplaceAllString(rest, "")
+	return strings.TrimRight(rest, " \t"), false
+}
+
+type replacement struct {
+	start, end int
+	text       string
+}
+
+func applyReplacements(s string, replacements []replacement) string {
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+	for _, r := range replacements {
+		s = s[:r.start] + r.text + s[r.end:]
+	}
+	return s
+}
+
+// addDuration adds whole days to the calendar date, so deadlines keep
+// their time of day across daylight saving time changes.
+func addDuration(t time.Time, d time.Duration) time.Time {
+	if day := 24 * time.Hour; d%day == 0 {
+		return t.AddDate(0, 0, int(d/day))
+	}
+	return t.Add(d)
+}
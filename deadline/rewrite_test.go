@@ -0,0 +1,95 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		name, line, note, want string
+	}{
+		{
+			"note appended",
+			"x := 1 // @CHECK(2025-03-01;WARN;;; Remove x)",
+			"postponed by 30d",
+			"x := 1 // @CHECK(2025-03-31;WARN;;; Remove x (postponed by 30d))",
+		},
+		{
+			"earlier note replaced",
+			"// @CHECK(2025-03-01;;;; Remove x (postponed by 7d: waiting))",
+			"postponed by 30d",
+			"// @CHECK(2025-03-31;;;; Remove x (postponed by 30d))",
+		},
+		{
+			"delimiters escaped",
+			"// @CHECK(2025-03-01;;;; Remove x)",
+			"postponed by 30d: see (a; b)",
+			"// @CHECK(2025-03-31;;;; Remove x (postponed by 30d: see [a, b]))",
+		},
+		{
+			"time of day kept",
+			"// @CHECK(2025-03-01T17:00+01:00;;;; Remove x)",
+			"postponed by 30d",
+			"// @CHECK(2025-03-31T17:00+01:00;;;; Remove x (postponed by 30d))",
+		},
+		{
+			"multibyte text before the annotation",
+			"ä := \"ö\" // @CHECK(2025-03-01;;;; Remove ä)",
+			"postponed by 30d",
+			"ä := \"ö\" // @CHECK(2025-03-31;;;; Remove ä (postponed by 30d))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scanString(t, Options{}, "main.go", tt.line+"\n")
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1", len(findings))
+			}
+			if got := findings[0].Bump(tt.line, 30*24*time.Hour, tt.note); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpSameLine(t *testing.T) {
+	line := "/* @CHECK(2025-03-01;;;; a) */ /* @CHECK(2025-04-01;;;; b) */"
+	findings := scanString(t, Options{}, "main.c", line+"\n")
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	// The annotation on the right is changed first, so the offsets of the
+	// left one stay valid.
+	got := findings[1].Bump(line, 24*time.Hour, "postponed by 1d")
+	got = findings[0].Bump(got, 24*time.Hour, "postponed by 1d")
+	want := "/* @CHECK(2025-03-02;;;; a (postponed by 1d)) */ /* @CHECK(2025-04-02;;;; b (postponed by 1d)) */"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name, file, line, want string
+		del                    bool
+	}{
+		{"whole line", "main.go", "\t// @CHECK(2025-03-01;;;; x)", "", true},
+		{"after code", "main.go", "x := 1 // @CHECK(2025-03-01;;;; x)", "x := 1", false},
+		{"with other comment", "main.go", "x := 1 // keep this @CHECK(2025-03-01;;;; x)", "x := 1 // keep this", false},
+		{"block comment", "main.c", "int x; /* @CHECK(2025-03-01;;;; x) */", "int x;", false},
+		{"html comment", "index.html", "<p></p> <!-- @CHECK(2025-03-01;;;; x) -->", "<p></p>", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scanString(t, Options{}, tt.file, tt.line+"\n")
+			if len(findings) != 1 {
+				t.Fatalf("got %d findings, want 1", len(findings))
+			}
+			got, del := findings[0].Remove(tt.line)
+			if got != tt.want || del != tt.del {
+				t.Errorf("got %q, %v, want %q, %v", got, del, tt.want, tt.del)
+			}
+		})
+	}
+}
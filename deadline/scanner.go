@@ -0,0 +1,459 @@
+package deadline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Clock tells the scanner what time it is. Pass a FixedClock to check the
+// deadlines against a different day.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FixedClock always returns the same time.
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time { return time.Time(c) }
+
+// Options configure a Scanner. The zero value scans for @CHECK annotations
+// with the system clock and one worker per CPU.
+type Options struct {
+	// WarnWithin marks deadlines expiring within this duration as
+	// StatusExpiring.
+	WarnWithin time.Duration
+	// MaxHorizon makes ScanChanges reject new deadlines further in the
+	// future than this.
+	MaxHorizon time.Duration
+	// Include and Exclude are globs in .gitignore syntax.
+	Include []string
+	Exclude []string
+	// NoDefaultExcludes also scans .git, node_modules and vendor
+	// directories.
+	NoDefaultExcludes bool
+	// Archives also scans the files in .zip, .jar, .tar, .tar.gz and .gz
+	// files. Their findings have virtual paths like bundle.zip!/src/x.go.
+	Archives bool
+	// MaxArchiveSize limits the decompressed bytes read from an archive,
+	// DefaultMaxArchiveSize if 0.
+	MaxArchiveSize int64
+	// UnknownAsText matches annotations anywhere in files of unknown type
+	// instead of skipping them.
+	UnknownAsText bool
+	Workers       int
+	// Config defines the annotation patterns and path overrides. If nil,
+	// only the built-in @CHECK pattern is used.
+	Config *Config
+	// Location is the zone of dates written without one. It defaults to
+	// the timezone of the config, or UTC.
+	Location *time.Location
+	// Calendar resolves relative dates and lists holidays. It defaults to
+	// the calendar file of the config.
+	Calendar *Calendar
+	// NextWorkingDay moves deadlines on weekends and holidays to the next
+	// working day. It is also enabled by the config.
+	NextWorkingDay bool
+	Clock          Clock
+	// Warn is called for problems which don't stop a scan, like a file
+	// which can't be read or an annotation with an invalid date. The
+	// errors are of type *ScanError or *os.PathError.
+	Warn func(err error)
+}
+
+// ScanError is a problem with a single annotation, or with reading a file
+// if Line is 0.
+type ScanError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ScanError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("Error scanning file: %s, error: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("%v in file: %s, line: %d", e.Err, e.File, e.Line)
+}
+
+func (e *ScanError) Unwrap() error { return e.Err }
+
+// Scanner finds annotations in files. It is safe for concurrent use.
+type Scanner struct {
+	opts   Options
+	config *config
+	dates  *dateResolver
+}
+
+// NewScanner returns a Scanner for opts. It fails if the config or the
+// calendar is invalid.
+func NewScanner(opts Options) (*Scanner, error) {
+	var cfg Config
+	if opts.Config != nil {
+		cfg = *opts.Config
+	}
+	c, err := compileConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := &dateResolver{loc: c.loc, nextWorkingDay: opts.NextWorkingDay || cfg.NextWorkingDay}
+	if opts.Location != nil {
+		dates.loc = opts.Location
+	}
+	cal := opts.Calendar
+	if cal == nil && cfg.Calendar != "" {
+		if cal, err = LoadCalendar(cfg.Calendar); err != nil {
+			return nil, err
+		}
+	}
+	if dates.calendar, err = compileCalendar(cal, dates.loc); err != nil {
+		return nil, fmt.Errorf("calendar: %v", err)
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = systemClock{}
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	return &Scanner{opts: opts, config: c, dates: dates}, nil
+}
+
+// Stream scans all files below root, which may also be a single file, and
+// sends every annotation to the returned channel as soon as it is found,
+// whether it is expired or not. The findings are not sorted. The error
+// channel receives at most one error and is closed after the findings
+// channel.
+func (s *Scanner) Stream(ctx context.Context, root string) (<-chan Finding, <-chan error) {
+	out := make(chan Finding)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(out)
+		err := s.run(ctx, root, s.walker(root).walk, func(findings []Finding) bool {
+			for _, f := range findings {
+				select {
+				case out <- f:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return out, errc
+}
+
+// ScanDir returns every annotation below root sorted by file and line.
+func (s *Scanner) ScanDir(ctx context.Context, root string) ([]Finding, error) {
+	return s.collect(ctx, root, s.walker(root).walk)
+}
+
+// ScanFiles scans the given files, which are not filtered by the include
+// and exclude globs. root is the directory the paths of the config
+// overrides are relative to.
+func (s *Scanner) ScanFiles(ctx context.Context, root string, files []string) ([]Finding, error) {
+	return s.collect(ctx, root, func(ctx context.Context, paths chan<- string) error {
+		for _, file := range files {
+			select {
+			case paths <- file:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// ScanChanges only scans the annotations on lines added or changed between
+// the git ref and HEAD in the repository at root. New annotations with a
+// deadline in the past or beyond Options.MaxHorizon get StatusRejected,
+// annotations which are neither rejected, expired nor expiring are dropped.
+func (s *Scanner) ScanChanges(ctx context.Context, root, ref string) ([]Finding, error) {
+	changed, err := changedLines(ctx, root, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.opts.Clock.Now()
+	w := s.walker(root)
+	findings, err := s.collect(ctx, root, func(ctx context.Context, paths chan<- string) error {
+		for _, file := range sortedKeys(changed) {
+			rel, err := filepath.Rel(root, file)
+			if err != nil || w.skip(filepath.ToSlash(rel)) {
+				continue
+			}
+			if err := w.send(ctx, paths, file); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	var result []Finding
+	for _, f := range findings {
+		if !changed[f.File][f.Line] {
+			continue
+		}
+		switch {
+		case f.Status == StatusExpired:
+			f.Status, f.Reason = StatusRejected, "deadline is already in the past"
+		case s.opts.MaxHorizon > 0 && f.Check.Deadline.After(now.Add(s.opts.MaxHorizon)):
+			f.Status, f.Reason = StatusRejected, fmt.Sprintf("deadline is more than %s in the future", FormatDuration(s.opts.MaxHorizon))
+		case f.Status == StatusOK:
+			continue
+		}
+		result = append(result, f)
+	}
+	return result, err
+}
+
+// ScanStaged scans the staged content of the files added or modified in
+// the git index of the repository at root, as a pre-commit hook sees them.
+// The include and exclude globs apply.
+func (s *Scanner) ScanStaged(ctx context.Context, root string) ([]Finding, error) {
+	files, err := stagedFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.opts.Clock.Now()
+	w := s.walker(root)
+	var findings []Finding
+	for _, name := range files {
+		if w.skip(name) {
+			continue
+		}
+		data, err := stagedContent(ctx, root, name)
+		if err != nil {
+			return findings, err
+		}
+		findings = append(findings, s.scan(ctx, filepath.Join(root, filepath.FromSlash(name)), name, bytes.NewReader(data), now)...)
+	}
+	return findings, ctx.Err()
+}
+
+// ScanReader returns the annotations in r. name is used as the File of the
+// findings, to pick the comment syntax and to match the config overrides.
+func (s *Scanner) ScanReader(ctx context.Context, name string, r io.Reader) []Finding {
+	return s.scan(ctx, name, filepath.ToSlash(name), r, s.opts.Clock.Now())
+}
+
+func (s *Scanner) walker(root string) *walker {
+	return newWalker(root, s.opts.Include, s.opts.Exclude, !s.opts.NoDefaultExcludes)
+}
+
+// collect runs a scan and returns the findings sorted by file and line.
+func (s *Scanner) collect(ctx context.Context, root string, produce func(context.Context, chan<- string) error) ([]Finding, error) {
+	var findings []Finding
+	err := s.run(ctx, root, produce, func(r []Finding) bool {
+		findings = append(findings, r...)
+		return true
+	})
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, err
+}
+
+// run scans the files produced by produce with a pool of workers and hands
+// the findings of each file to emit, until emit returns false.
+func (s *Scanner) run(ctx context.Context, root string, produce func(context.Context, chan<- string) error, emit func([]Finding) bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	now := s.opts.Clock.Now()
+	paths := make(chan string)
+	results := make(chan []Finding)
+
+	var walkErr error
+	go func() {
+		defer close(paths)
+		walkErr = produce(ctx, paths)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				select {
+				case results <- s.processFile(ctx, root, path, now):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		if !emit(r) {
+			cancel()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return walkErr
+}
+
+// processFile returns all annotations found in a file.
+func (s *Scanner) processFile(ctx context.Context, root, filename string, now time.Time) []Finding {
+	file, err := os.Open(filename)
+	if err != nil {
+		s.warn(err)
+		return nil
+	}
+	defer file.Close()
+
+	rel, err := filepath.Rel(root, filename)
+	if err != nil {
+		rel = filename
+	}
+	if s.opts.Archives && archiveKind(filename) != "" {
+		return s.newArchiveScan(now).scanFile(ctx, filename, filepath.ToSlash(rel), file)
+	}
+	return s.scan(ctx, filename, filepath.ToSlash(rel), file, now)
+}
+
+// scan returns the annotations in r. rel is the slash separated path
+// matched against the config overrides.
+func (s *Scanner) scan(ctx context.Context, filename, rel string, r io.Reader, now time.Time) []Finding {
+	reader := bufio.NewReaderSize(r, 8192)
+	if head, _ := reader.Peek(8000); isBinary(head) {
+		return nil
+	}
+
+	patterns, severity := s.config.forFile(rel)
+
+	// Annotations only count in comments, unless the language is unknown
+	// and UnknownAsText is set.
+	lang := languageFor(filename)
+	if lang == nil {
+		if !s.opts.UnknownAsText {
+			return nil
+		}
+		lang = langText
+	}
+	masker := newCommentMasker(lang)
+
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	var findings []Finding
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+		lineNumber++
+		line := scanner.Text()
+		masked := masker.mask(line)
+
+		for _, p := range patterns {
+			for _, loc := range p.re.FindAllStringSubmatchIndex(masked, -1) {
+				check, err := p.parseCheck(submatches(masked, loc), s.dates)
+				if err != nil {
+					s.warn(&ScanError{File: filename, Line: lineNumber, Err: err})
+					if check.Deadline.IsZero() {
+						continue
+					}
+				}
+				if severity != "" {
+					check.Severity = severity
+				}
+				findings = append(findings, s.newFinding(filename, lineNumber, line, p, loc, check, now))
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		s.warn(&ScanError{File: filename, Err: err})
+	}
+
+	seen := make(map[string]int)
+	for i := range findings {
+		fp := Fingerprint(rel, findings[i].Check)
+		seen[fp]++
+		if n := seen[fp]; n > 1 {
+			fp = fmt.Sprintf("%s-%d", fp, n)
+		}
+		findings[i].Fingerprint = fp
+	}
+	return findings
+}
+
+func (s *Scanner) warn(err error) {
+	if s.opts.Warn != nil {
+		s.opts.Warn(err)
+	}
+}
+
+// newFinding classifies an annotation matched by p at loc in line.
+func (s *Scanner) newFinding(filename string, lineNumber int, line string, p *pattern, loc []int, check Check, now time.Time) Finding {
+	finding := Finding{
+		File:    filename,
+		Line:    lineNumber,
+		Column:  utf8.RuneCountInString(line[:loc[0]]) + 1,
+		Text:    line,
+		Check:   check,
+		pattern: p,
+		match:   loc,
+	}
+	s.classify(&finding, now)
+	return finding
+}
+
+// classify sets the status of f at the time now.
+func (s *Scanner) classify(f *Finding, now time.Time) {
+	deadline := f.Check.Deadline
+	switch {
+	case now.After(deadline):
+		f.Status = StatusExpired
+		f.DaysOverdue = int(now.Sub(deadline).Hours() / 24)
+	case now.Add(s.opts.WarnWithin).After(deadline):
+		f.Status = StatusExpiring
+	default:
+		f.Status = StatusOK
+	}
+	if f.Status != StatusExpired {
+		f.DaysOverdue = -int(math.Ceil(deadline.Sub(now).Hours() / 24))
+	}
+}
+
+// submatches turns the index pairs of FindStringSubmatchIndex back into
+// strings, just like FindStringSubmatch would.
+func submatches(s string, loc []int) []string {
+	matches := make([]string, len(loc)/2)
+	for i := range matches {
+		if loc[2*i] >= 0 {
+			matches[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return matches
+}
@@ -0,0 +1,81 @@
+package deadline
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// now is the time of all tests, a Monday.
+var now = time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+// scanString scans content as the file name with a scanner for opts. The
+// clock is fixed at now unless opts has one.
+func scanString(t *testing.T, opts Options, name, content string) []Finding {
+	t.Helper()
+	if opts.Clock == nil {
+		opts.Clock = FixedClock(now)
+	}
+	s, err := NewScanner(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.ScanReader(context.Background(), name, strings.NewReader(content))
+}
+
+func TestScanStatus(t *testing.T) {
+	tests := []struct {
+		date        string
+		status      Status
+		daysOverdue int
+	}{
+		{"2025-03-01", StatusExpired, 9},
+		{"2025-03-10T11:00Z", StatusExpired, 0},
+		{"2025-03-12", StatusExpiring, -2},
+		{"2025-04-01", StatusOK, -22},
+	}
+	for _, tt := range tests {
+		content := "// @CHECK(" + tt.date + ";WARN;alice;cleanup; Remove the flag)\n"
+		findings := scanString(t, Options{WarnWithin: 7 * 24 * time.Hour}, "main.go", content)
+		if len(findings) != 1 {
+			t.Fatalf("%s: got %d findings, want 1", tt.date, len(findings))
+		}
+		f := findings[0]
+		if f.Status != tt.status || f.DaysOverdue != tt.daysOverdue {
+			t.Errorf("%s: got %s, %d days overdue, want %s, %d", tt.date, f.Status, f.DaysOverdue, tt.status, tt.daysOverdue)
+		}
+		if f.Check.Severity != SeverityWarn || f.Check.Owner != "alice" || f.Check.Description != "Remove the flag" {
+			t.Errorf("%s: got %+v", tt.date, f.Check)
+		}
+	}
+}
+
+func TestScanTimezone(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	tests := []struct {
+		now    time.Time
+		status Status
+	}{
+		// Midnight in Berlin is 23:00 UTC on the day before.
+		{time.Date(2025, time.March, 13, 22, 59, 0, 0, time.UTC), StatusOK},
+		{time.Date(2025, time.March, 13, 23, 1, 0, 0, time.UTC), StatusExpired},
+	}
+	for _, tt := range tests {
+		findings := scanString(t, Options{Location: berlin, Clock: FixedClock(tt.now)}, "main.go", "// @CHECK(2025-03-14;;;; x)\n")
+		if len(findings) != 1 || findings[0].Status != tt.status {
+			t.Errorf("at %s: got %+v, want status %s", tt.now, findings, tt.status)
+		}
+	}
+}
+
+func TestScanDuplicateFingerprints(t *testing.T) {
+	content := "// @CHECK(2025-03-01;;;; same)\n// @CHECK(2025-04-01;;;; same)\n"
+	findings := scanString(t, Options{}, "main.go", content)
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	if want := findings[0].Fingerprint + "-2"; findings[1].Fingerprint != want {
+		t.Errorf("got fingerprint %s, want %s", findings[1].Fingerprint, want)
+	}
+}
@@ -0,0 +1,126 @@
+package deadline
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// walker walks a directory tree and sends the files which should be scanned
+// to a channel. It honors the ignore files found in the tree as well as the
+// include and exclude globs.
+type walker struct {
+	root    string
+	include ignoreList
+	exclude ignoreList
+	// onDir is called for every directory walked with the ignore
+	// patterns which apply to its entries.
+	onDir func(dir, rel string, ignored ignoreList)
+}
+
+func newWalker(root string, include, exclude []string, useDefaults bool) *walker {
+	w := &walker{
+		root:    root,
+		include: parseIgnorePatterns(include, ""),
+		exclude: parseIgnorePatterns(exclude, ""),
+	}
+	if useDefaults {
+		w.exclude = append(parseIgnorePatterns(defaultExcludes, ""), w.exclude...)
+	}
+	return w
+}
+
+// walk sends the paths of all files to scan to paths. It stops as soon as
+// ctx is cancelled.
+func (w *walker) walk(ctx context.Context, paths chan<- string) error {
+	info, err := os.Stat(w.root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return w.send(ctx, paths, w.root)
+	}
+	return w.walkDir(ctx, paths, w.root, "", readIgnoreFiles(w.root, ""))
+}
+
+func (w *walker) walkDir(ctx context.Context, paths chan<- string, dir, rel string, ignored ignoreList) error {
+	if w.onDir != nil {
+		w.onDir(dir, rel, ignored)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name := entry.Name()
+		full := filepath.Join(dir, name)
+		relPath := path.Join(rel, name)
+		isDir := entry.IsDir()
+
+		if !w.accept(relPath, isDir, ignored) {
+			continue
+		}
+
+		if isDir {
+			sub := append(ignored[:len(ignored):len(ignored)], readIgnoreFiles(full, relPath)...)
+			if err := w.walkDir(ctx, paths, full, relPath, sub); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Links to files are scanned, links to directories aren't followed
+		// and broken links are skipped.
+		if entry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(full); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+		} else if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := w.send(ctx, paths, full); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accept reports whether the entry with the slash separated path rel is
+// walked, given the ignore patterns of its directory.
+func (w *walker) accept(rel string, isDir bool, ignored ignoreList) bool {
+	if ignored.match(rel, isDir) || w.exclude.match(rel, isDir) {
+		return false
+	}
+	return isDir || len(w.include) == 0 || w.include.match(rel, false)
+}
+
+// skip reports whether the file with the slash separated path rel is
+// excluded by the globs of the walker. Ignore files are not consulted.
+func (w *walker) skip(rel string) bool {
+	if w.exclude.matchPath(rel) {
+		return true
+	}
+	return len(w.include) > 0 && !w.include.match(rel, false)
+}
+
+func (w *walker) send(ctx context.Context, paths chan<- string, p string) error {
+	select {
+	case paths <- p:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isBinary uses the same heuristic as git: a file containing a NUL byte
+// within its first few kilobytes is binary.
+func isBinary(head []byte) bool {
+	return bytes.IndexByte(head, 0) >= 0
+}
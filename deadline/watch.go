@@ -0,0 +1,231 @@
+package deadline
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDelay collects the events of an editor saving a file, which often
+// writes, renames and chmods it in a row, into a single rescan.
+const watchDelay = 100 * time.Millisecond
+
+// Watch scans root like ScanDir and calls update with all findings. It then
+// watches the directories below root for changes, rescans only the changed
+// files and calls update again. The statuses are refreshed every minute,
+// so deadlines expire while watching. Watch returns when ctx is cancelled.
+func (s *Scanner) Watch(ctx context.Context, root string, update func([]Finding)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	ws := &watchState{
+		s:       s,
+		root:    root,
+		w:       s.walker(root),
+		watcher: watcher,
+	}
+	if err := ws.rescan(ctx); err != nil {
+		return err
+	}
+	update(ws.findings())
+
+	pending := make(map[string]bool)
+	var delay <-chan time.Time
+	tick := time.NewTicker(time.Minute)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			pending[filepath.Clean(ev.Name)] = true
+			if delay == nil {
+				delay = time.After(watchDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			s.warn(err)
+		case <-delay:
+			delay = nil
+			if err := ws.apply(ctx, pending); err != nil {
+				return err
+			}
+			pending = make(map[string]bool)
+			update(ws.findings())
+		case <-tick.C:
+			ws.refresh(s.opts.Clock.Now())
+			update(ws.findings())
+		}
+	}
+}
+
+// watchedDir is a directory below the root with the ignore patterns which
+// apply to its entries.
+type watchedDir struct {
+	rel     string
+	ignored ignoreList
+}
+
+type watchState struct {
+	s       *Scanner
+	root    string
+	w       *walker
+	watcher *fsnotify.Watcher
+	dirs    map[string]watchedDir
+	files   map[string][]Finding
+}
+
+// rescan scans the whole tree again and watches all its directories.
+func (ws *watchState) rescan(ctx context.Context) error {
+	for dir := range ws.dirs {
+		ws.watcher.Remove(dir)
+	}
+	ws.dirs = make(map[string]watchedDir)
+	ws.files = make(map[string][]Finding)
+
+	if info, err := os.Stat(ws.root); err == nil && !info.IsDir() {
+		if err := ws.watcher.Add(ws.root); err != nil {
+			return err
+		}
+		return ws.scan(ctx, ws.w.walk)
+	}
+
+	ws.w.onDir = ws.watch
+	defer func() { ws.w.onDir = nil }()
+	return ws.scan(ctx, ws.w.walk)
+}
+
+func (ws *watchState) watch(dir, rel string, ignored ignoreList) {
+	ws.dirs[dir] = watchedDir{rel: rel, ignored: ignored}
+	if err := ws.watcher.Add(dir); err != nil {
+		ws.s.warn(err)
+	}
+}
+
+// scan scans the produced files with the same workers as ScanDir and adds
+// their findings. The old findings must have been forgotten before.
+func (ws *watchState) scan(ctx context.Context, produce func(context.Context, chan<- string) error) error {
+	return ws.s.run(ctx, ws.root, produce, func(findings []Finding) bool {
+		for _, f := range findings {
+			ws.files[f.File] = append(ws.files[f.File], f)
+		}
+		return true
+	})
+}
+
+// apply rescans the changed paths. New directories are walked, removed
+// files and directories are dropped. A changed ignore file rescans the
+// whole tree.
+func (ws *watchState) apply(ctx context.Context, changed map[string]bool) error {
+	for p := range changed {
+		if name := filepath.Base(p); name == ignoreFiles[0] || name == ignoreFiles[1] {
+			return ws.rescan(ctx)
+		}
+	}
+
+	var files []string
+	for p := range changed {
+		ws.forget(p)
+
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		if p == ws.root {
+			files = append(files, p)
+			continue
+		}
+		parent, ok := ws.dirs[filepath.Dir(p)]
+		if !ok {
+			continue
+		}
+		rel := path.Join(parent.rel, filepath.Base(p))
+		if !ws.w.accept(rel, info.IsDir(), parent.ignored) {
+			continue
+		}
+
+		if info.IsDir() {
+			ignored := append(parent.ignored[:len(parent.ignored):len(parent.ignored)], readIgnoreFiles(p, rel)...)
+			ws.w.onDir = ws.watch
+			err := ws.scan(ctx, func(ctx context.Context, paths chan<- string) error {
+				return ws.w.walkDir(ctx, paths, p, rel, ignored)
+			})
+			ws.w.onDir = nil
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, p)
+		}
+	}
+
+	// A file may also have been scanned with its new directory.
+	for _, file := range files {
+		ws.forget(file)
+	}
+	return ws.scan(ctx, func(ctx context.Context, paths chan<- string) error {
+		for _, file := range files {
+			if err := ws.w.send(ctx, paths, file); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// forget drops the findings of the file or directory p, including those
+// in an archive p, whose paths look like p!/x.go.
+func (ws *watchState) forget(p string) {
+	prefix := p + string(filepath.Separator)
+	for file := range ws.files {
+		if file == p || strings.HasPrefix(file, prefix) || strings.HasPrefix(file, p+"!/") {
+			delete(ws.files, file)
+		}
+	}
+	for dir := range ws.dirs {
+		if dir == p || strings.HasPrefix(dir, prefix) {
+			delete(ws.dirs, dir)
+		}
+	}
+}
+
+// refresh updates the statuses of all findings.
+func (ws *watchState) refresh(now time.Time) {
+	for _, findings := range ws.files {
+		for i := range findings {
+			ws.s.classify(&findings[i], now)
+		}
+	}
+}
+
+// findings returns all findings sorted by file and line.
+func (ws *watchState) findings() []Finding {
+	var all []Finding
+	for _, findings := range ws.files {
+		all = append(all, findings...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].File != all[j].File {
+			return all[i].File < all[j].File
+		}
+		return all[i].Line < all[j].Line
+	})
+	return all
+}
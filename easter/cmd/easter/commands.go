@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SimonWaldherr/gotools/easter"
+)
+
+// parseArgs parses the flags of fs, which may also follow the positional
+// arguments like in "workdays 2025-01-01 2025-12-31 -region DE-BY", and
+// returns the positional arguments. Negative numbers are positional.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for len(args) > 0 {
+		end := len(args)
+		for i, arg := range args {
+			if isNegativeNumber(arg) {
+				end = i
+				break
+			}
+		}
+		fs.Parse(args[:end])
+		rest := fs.Args()
+		args = args[end:]
+		if len(rest) == 0 {
+			if len(args) > 0 {
+				positional = append(positional, args[0])
+				args = args[1:]
+			}
+			continue
+		}
+		positional = append(positional, rest[0])
+		args = append(rest[1:len(rest):len(rest)], args...)
+	}
+	return positional
+}
+
+func isNegativeNumber(arg string) bool {
+	_, err := strconv.Atoi(arg)
+	return err == nil && strings.HasPrefix(arg, "-")
+}
+
+// parseDay parses a date in the format YYYY-MM-DD.
+func parseDay(s string) (time.Time, error) {
+	day, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return day, fmt.Errorf("invalid date %q, use YYYY-MM-DD", s)
+	}
+	return day, nil
+}
+
+// workCalendarFlags adds the -region flag to fs.
+func workCalendarFlags(fs *flag.FlagSet) *string {
+	return fs.String("region", "", "region whose public holidays are days off, e.g. DE-BY (default: only weekends)")
+}
+
+// runWorkdays prints the number of working days between two dates.
+func runWorkdays(args []string) int {
+	fs := flag.NewFlagSet("workdays", flag.ExitOnError)
+	region := workCalendarFlags(fs)
+	args = parseArgs(fs, args)
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: easter workdays <start> <end> [-region DE-BY]")
+		return 2
+	}
+
+	start, err := parseDay(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	end, err := parseDay(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if end.Before(start) {
+		fmt.Fprintln(os.Stderr, "the end is before the start")
+		return 2
+	}
+	cal, err := easter.NewWorkCalendar(*region)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	fmt.Println(cal.Workdays(start, end))
+	return 0
+}
+
+// runAddWorkdays prints the date n working days after a date.
+func runAddWorkdays(args []string) int {
+	fs := flag.NewFlagSet("add-workdays", flag.ExitOnError)
+	region := workCalendarFlags(fs)
+	args = parseArgs(fs, args)
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: easter add-workdays <date> <n> [-region DE-BY]")
+		return 2
+	}
+
+	day, err := parseDay(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid number of working days: %s\n", args[1])
+		return 2
+	}
+	cal, err := easter.NewWorkCalendar(*region)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	fmt.Println(cal.AddWorkdays(day, n).Format("2006-01-02"))
+	return 0
+}
+
+// runIsHoliday exits with 0 if the date is a public holiday and with 1 if
+// it isn't.
+func runIsHoliday(args []string) int {
+	fs := flag.NewFlagSet("is-holiday", flag.ExitOnError)
+	region := workCalendarFlags(fs)
+	weekends := fs.Bool("weekends", false, "also count Saturdays and Sundays as holidays")
+	quiet := fs.Bool("q", false, "don't print the name of the holiday")
+	args = parseArgs(fs, args)
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: easter is-holiday <date> [-region DE-BY] [-weekends] [-q]")
+		return 2
+	}
+
+	day, err := parseDay(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	cal, err := easter.NewWorkCalendar(*region)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	name, holiday := cal.Holiday(day)
+	if !holiday && *weekends {
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			name, holiday = wd.String(), true
+		}
+	}
+	if !holiday {
+		return 1
+	}
+	if !*quiet {
+		fmt.Println(name)
+	}
+	return 0
+}
@@ -0,0 +1,182 @@
+// Description: Calculates the date of Easter for a given year using the Gauss Easter formula
+// or, with -method, the Meeus/Jones/Butcher, anonymous Gregorian or Orthodox computus.
+// The calculations are the importable package github.com/SimonWaldherr/gotools/easter.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SimonWaldherr/gotools/easter"
+)
+
+// commands are the subcommands of the tool. Without a subcommand, the
+// dates of Easter and the holidays are printed.
+var commands = map[string]func(args []string) int{
+	"workdays":     runWorkdays,
+	"add-workdays": runAddWorkdays,
+	"is-holiday":   runIsHoliday,
+	"serve":        runServe,
+}
+
+func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, ok := commands[args[0]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command: %s\nUsage: easter [workdays|add-workdays|is-holiday|serve] [flags]\n", args[0])
+			os.Exit(2)
+		}
+		os.Exit(cmd(args[1:]))
+	}
+	os.Exit(runEaster(args))
+}
+
+func runEaster(args []string) int {
+	var year, from, to int
+	var feasts, details bool
+	var methodName, check, region, format string
+	var currentYear = time.Now().Year()
+	fs := flag.NewFlagSet("easter", flag.ExitOnError)
+	fs.IntVar(&year, "year", currentYear, "year")
+	fs.IntVar(&from, "from", 0, "first year of a range of years (default: -year)")
+	fs.IntVar(&to, "to", 0, "last year of a range of years (default: -from)")
+	fs.BoolVar(&feasts, "feasts", false, "print all movable feasts of the year")
+	fs.BoolVar(&details, "details", false, "also print the paschal full moon, Passover, ISO week and day of the year")
+	fs.StringVar(&methodName, "method", "gauss", "computus: "+strings.Join(easter.MethodNames(), ", "))
+	fs.StringVar(&check, "self-check", "", "cross-validate all methods for a range of years, e.g. 1583-4099")
+	fs.StringVar(&region, "region", "", "print the public holidays of a region, e.g. DE-BY, AT or CH-ZH (list: all regions)")
+	fs.StringVar(&format, "format", "text", "holiday output format: text, json, csv or ics")
+	fs.Parse(args)
+
+	if check != "" {
+		var first, last int
+		if _, err := fmt.Sscanf(check, "%d-%d", &first, &last); err != nil || first > last {
+			fmt.Fprintf(os.Stderr, "invalid -self-check range: %s\n", check)
+			return 2
+		}
+		problems := easter.SelfCheck(first, last)
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		if len(problems) > 0 {
+			return 1
+		}
+		fmt.Printf("All methods agree for the years %d to %d\n", first, last)
+		return 0
+	}
+
+	if from == 0 {
+		from = year
+	}
+	if to == 0 {
+		to = from
+	}
+	if from > to {
+		fmt.Fprintf(os.Stderr, "invalid year range: %d to %d\n", from, to)
+		return 2
+	}
+
+	if region == "list" {
+		for _, code := range easter.RegionCodes() {
+			fmt.Printf("%-6s %s\n", code, easter.Regions[code])
+		}
+		return 0
+	}
+	if region != "" {
+		write, ok := writers[format]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown format: %s\n", format)
+			return 2
+		}
+		var holidays []easter.Holiday
+		for y := from; y <= to; y++ {
+			h, err := easter.Holidays(region, y)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+			holidays = append(holidays, h...)
+		}
+		if err := write(os.Stdout, strings.ToUpper(region), holidays); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	method, err := easter.ParseMethod(methodName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	for y := from; y <= to; y++ {
+		var err error
+		switch {
+		case feasts:
+			err = printFeasts(os.Stdout, method, y, from != to)
+		case details:
+			err = printDetails(os.Stdout, method, y)
+		default:
+			var e time.Time
+			if e, err = method.Easter(y); err == nil {
+				fmt.Printf("Easter in %d is on %d.%d\n", y, e.Day(), int(e.Month()))
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+	return 0
+}
+
+// printFeasts prints the movable feasts of a year, with a heading if
+// several years are printed.
+func printFeasts(w io.Writer, method easter.Method, year int, heading bool) error {
+	feasts, err := method.MovableFeasts(year)
+	if err != nil {
+		return err
+	}
+	if heading {
+		fmt.Fprintf(w, "%d\n", year)
+	}
+	for _, f := range feasts {
+		fmt.Fprintf(w, "%-24s %s\n", f.Name, f.Date.Format("Mon 02.01.2006"))
+	}
+	if heading {
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// printDetails prints Easter, its paschal full moon and Passover of a year
+// with their ISO weeks and days of the year.
+func printDetails(w io.Writer, method easter.Method, year int) error {
+	sunday, err := method.Easter(year)
+	if err != nil {
+		return err
+	}
+	moon, err := method.PaschalFullMoon(year)
+	if err != nil {
+		return err
+	}
+	passover, err := easter.Passover(year)
+	if err != nil {
+		return err
+	}
+	for _, f := range []easter.Feast{
+		{Name: "Easter Sunday", Date: sunday},
+		{Name: "Paschal full moon", Date: moon},
+		{Name: "Passover", Date: passover},
+	} {
+		_, week := easter.ISOWeek(f.Date)
+		fmt.Fprintf(w, "%-24s %s  week %2d  day %3d\n", f.Name, f.Date.Format("Mon 02.01.2006"), week, easter.DayOfYear(f.Date))
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SimonWaldherr/gotools/easter"
+)
+
+// writers print the holidays of a region in the formats selectable with
+// -format.
+var writers = map[string]func(w io.Writer, region string, holidays []easter.Holiday) error{
+	"text": writeText,
+	"json": writeJSON,
+	"csv":  writeCSV,
+	"ics":  writeICS,
+}
+
+func writeText(w io.Writer, region string, holidays []easter.Holiday) error {
+	for _, h := range holidays {
+		if _, err := fmt.Fprintf(w, "%s  %s\n", h.Date.Format("Mon 02.01.2006"), h.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonHoliday struct {
+	Date      string `json:"date"`
+	Name      string `json:"name"`
+	Region    string `json:"region"`
+	Week      int    `json:"week"`
+	DayOfYear int    `json:"dayOfYear"`
+}
+
+func writeJSON(w io.Writer, region string, holidays []easter.Holiday) error {
+	out := make([]jsonHoliday, 0, len(holidays))
+	for _, h := range holidays {
+		_, week := easter.ISOWeek(h.Date)
+		out = append(out, jsonHoliday{
+			Date:      h.Date.Format("2006-01-02"),
+			Name:      h.Name,
+			Region:    region,
+			Week:      week,
+			DayOfYear: easter.DayOfYear(h.Date),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeCSV(w io.Writer, region string, holidays []easter.Holiday) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "name", "region"})
+	for _, h := range holidays {
+		cw.Write([]string{h.Date.Format("2006-01-02"), h.Name, region})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeICS writes the holidays as an RFC 5545 calendar of all-day events.
+func writeICS(w io.Writer, region string, holidays []easter.Holiday) error {
+	return writeCalendar(w, "Holidays "+region, region, holidays)
+}
+
+// writeCalendar writes the days as an RFC 5545 calendar of all-day events.
+// The UIDs of the events are made of the date, key and name.
+func writeCalendar(w io.Writer, name, key string, days []easter.Holiday) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//SimonWaldherr//gotools easter//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + icsEscape(name),
+	}
+	for _, h := range days {
+		uid := fnv.New32a()
+		uid.Write([]byte(h.Name))
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%s-%08x@gotools", h.Date.Format("20060102"), key, uid.Sum32()),
+			// The holidays only change with the rules, so the stamp is
+			// fixed and the calendar stays the same between runs.
+			"DTSTAMP:"+h.Date.Format("20060102T150405Z"),
+			"DTSTART;VALUE=DATE:"+h.Date.Format("20060102"),
+			"DTEND;VALUE=DATE:"+h.Date.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+icsEscape(h.Name),
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, icsFold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// icsEscape escapes a TEXT value.
+var icsEscape = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace
+
+// icsFold folds a content line after 75 octets without splitting a UTF-8
+// sequence.
+func icsFold(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts against the next line.
+		limit = 74
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SimonWaldherr/gotools/easter"
+)
+
+// runServe serves Easter, the holidays and working days over HTTP.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	fs.Parse(args)
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServeMux(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		shutdown, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		srv.Shutdown(shutdown)
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Print(err)
+		return 1
+	}
+	return 0
+}
+
+func newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /easter/{year}", handleEaster)
+	mux.HandleFunc("GET /holidays/{region}/{year}", handleHolidays)
+	mux.HandleFunc("GET /workdays", handleWorkdays)
+	return mux
+}
+
+// mediaTypes are the formats of the responses in order of preference.
+var mediaTypes = []struct {
+	format, mediaType string
+}{
+	{"json", "application/json"},
+	{"ics", "text/calendar"},
+	{"csv", "text/csv"},
+	{"text", "text/plain"},
+}
+
+// renderers write a response in one of the formats.
+type renderers map[string]func(io.Writer) error
+
+type jsonFeast struct {
+	Name string `json:"name"`
+	Date string `json:"date"`
+}
+
+type jsonEaster struct {
+	Year            int         `json:"year"`
+	Method          string      `json:"method"`
+	Easter          string      `json:"easter"`
+	PaschalFullMoon string      `json:"paschalFullMoon"`
+	Passover        string      `json:"passover"`
+	Feasts          []jsonFeast `json:"feasts"`
+}
+
+// handleEaster serves Easter and the movable feasts of a year. The method
+// is selected with ?method=.
+func handleEaster(w http.ResponseWriter, r *http.Request) {
+	year, format, err := pathYear(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	method := easter.Gauss
+	if name := r.URL.Query().Get("method"); name != "" {
+		if method, err = easter.ParseMethod(name); err != nil {
+			httpError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	sunday, err := method.Easter(year)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	moon, _ := method.PaschalFullMoon(year)
+	passover, _ := easter.Passover(year)
+	feasts, _ := method.MovableFeasts(year)
+	days := make([]easter.Holiday, 0, len(feasts))
+	for _, f := range feasts {
+		days = append(days, easter.Holiday{Date: f.Date, Name: f.Name})
+	}
+
+	respond(w, r, format, renderers{
+		"json": func(w io.Writer) error {
+			out := jsonEaster{
+				Year:            year,
+				Method:          string(method),
+				Easter:          sunday.Format("2006-01-02"),
+				PaschalFullMoon: moon.Format("2006-01-02"),
+				Passover:        passover.Format("2006-01-02"),
+				Feasts:          []jsonFeast{},
+			}
+			for _, f := range feasts {
+				out.Feasts = append(out.Feasts, jsonFeast{Name: f.Name, Date: f.Date.Format("2006-01-02")})
+			}
+			return json.NewEncoder(w).Encode(out)
+		},
+		"ics": func(w io.Writer) error {
+			return writeCalendar(w, fmt.Sprintf("Movable feasts %d (%s)", year, method), string(method), days)
+		},
+		"csv": func(w io.Writer) error {
+			cw := csv.NewWriter(w)
+			cw.Write([]string{"date", "name"})
+			for _, f := range feasts {
+				cw.Write([]string{f.Date.Format("2006-01-02"), f.Name})
+			}
+			cw.Flush()
+			return cw.Error()
+		},
+		"text": func(w io.Writer) error {
+			return printFeasts(w, method, year, false)
+		},
+	})
+}
+
+// handleHolidays serves the public holidays of a region in a year.
+func handleHolidays(w http.ResponseWriter, r *http.Request) {
+	year, format, err := pathYear(r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	region := strings.ToUpper(r.PathValue("region"))
+	if _, ok := easter.Regions[region]; !ok {
+		httpError(w, http.StatusNotFound, fmt.Errorf("unknown region: %s", region))
+		return
+	}
+	holidays, err := easter.Holidays(region, year)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	out := renderers{}
+	for format, write := range writers {
+		out[format] = func(w io.Writer) error { return write(w, region, holidays) }
+	}
+	respond(w, r, format, out)
+}
+
+type jsonWorkdays struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Region   string `json:"region,omitempty"`
+	Workdays int    `json:"workdays"`
+}
+
+// handleWorkdays serves the number of working days from ?from= to ?to=,
+// both included, with the holidays of ?region=.
+func handleWorkdays(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, err := parseDay(q.Get("from"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, fmt.Errorf("from: %v", err))
+		return
+	}
+	to, err := parseDay(q.Get("to"))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, fmt.Errorf("to: %v", err))
+		return
+	}
+	if to.Before(from) {
+		httpError(w, http.StatusBadRequest, errors.New("to is before from"))
+		return
+	}
+	// Counting is linear in the days, so the range is limited.
+	if to.Sub(from) > 100*366*24*time.Hour {
+		httpError(w, http.StatusBadRequest, errors.New("the range is longer than 100 years"))
+		return
+	}
+	region := strings.ToUpper(q.Get("region"))
+	cal, err := easter.NewWorkCalendar(region)
+	if err != nil {
+		httpError(w, http.StatusNotFound, err)
+		return
+	}
+	n := cal.Workdays(from, to)
+
+	respond(w, r, q.Get("format"), renderers{
+		"json": func(w io.Writer) error {
+			return json.NewEncoder(w).Encode(jsonWorkdays{
+				From:     from.Format("2006-01-02"),
+				To:       to.Format("2006-01-02"),
+				Region:   region,
+				Workdays: n,
+			})
+		},
+		"text": func(w io.Writer) error {
+			_, err := fmt.Fprintln(w, n)
+			return err
+		},
+	})
+}
+
+// pathYear returns the year of the path and the format given by an
+// extension like 2025.ics or ?format=.
+func pathYear(r *http.Request) (int, string, error) {
+	s := r.PathValue("year")
+	format := r.URL.Query().Get("format")
+	if i := strings.LastIndexByte(s, '.'); i >= 0 {
+		s, format = s[:i], s[i+1:]
+	}
+	year, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid year: %s", s)
+	}
+	return year, format, nil
+}
+
+// respond writes the response in the requested format, or the one the
+// Accept header prefers. Identical responses get the same ETag, so
+// clients can revalidate with If-None-Match.
+func respond(w http.ResponseWriter, r *http.Request, format string, render renderers) {
+	if format == "" {
+		format = negotiate(r.Header.Get("Accept"), render)
+		if format == "" {
+			httpError(w, http.StatusNotAcceptable, errors.New("none of the accepted media types is available"))
+			return
+		}
+	}
+	write, ok := render[format]
+	if !ok {
+		httpError(w, http.StatusNotAcceptable, fmt.Errorf("unsupported format: %s", format))
+		return
+	}
+
+	var body bytes.Buffer
+	if err := write(&body); err != nil {
+		httpError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sum := sha256.Sum256(append([]byte(format), body.Bytes()...))
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	h := w.Header()
+	h.Set("Vary", "Accept")
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", "public, max-age=86400")
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	for _, mt := range mediaTypes {
+		if mt.format == format {
+			h.Set("Content-Type", mime.FormatMediaType(mt.mediaType, map[string]string{"charset": "utf-8"}))
+		}
+	}
+	if format == "ics" {
+		h.Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	}
+	w.Write(body.Bytes())
+}
+
+// negotiate returns the format of the available media type with the
+// highest quality in the Accept header, or "" if none is acceptable.
+// Without an Accept header, JSON is preferred.
+func negotiate(accept string, render renderers) string {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+	best, bestQ := "", 0.0
+	for _, mt := range mediaTypes {
+		if _, ok := render[mt.format]; !ok {
+			continue
+		}
+		if q := acceptQuality(accept, mt.mediaType); q > bestQ {
+			best, bestQ = mt.format, q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the quality the Accept header gives mediaType. The
+// most specific matching range counts.
+func acceptQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		r, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		s := -1
+		switch {
+		case r == mediaType:
+			s = 2
+		case r == typ+"/*":
+			s = 1
+		case r == "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q = s, 1
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				q = 0
+			}
+		}
+	}
+	return q
+}
+
+// matchETag reports whether the If-None-Match header matches etag.
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// httpError writes err as a JSON error response.
+func httpError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
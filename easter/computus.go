@@ -0,0 +1,188 @@
+package easter
+
+import (
+	"fmt"
+	"time"
+)
+
+// gaussEasterFormula calculates the day and month of Easter for a given
+// year using the Gauss Easter formula.
+func gaussEasterFormula(year int) (int, int) {
+	M, d := gaussMoon(year)
+	b := year % 4
+	c := year % 7
+	k := year / 100
+	N := (4 + k - k/4) % 7
+	e := (2*b + 4*c + 6*d + N) % 7
+
+	// Gauss' two exceptions keep Easter on or before the 25th of April.
+	switch {
+	case d == 29 && e == 6:
+		return 19, 4
+	case d == 28 && e == 6 && (11*M+11)%30 < 19:
+		return 18, 4
+	}
+	if 22+d+e > 31 {
+		return d + e - 9, 4
+	}
+	return 22 + d + e, 3
+}
+
+// gaussMoon returns Gauss' secular correction M and the days d from the
+// 21st of March to the paschal full moon before his exceptions.
+func gaussMoon(year int) (M, d int) {
+	a := year % 19
+	k := year / 100
+	p := (13 + 8*k) / 25
+	M = (15 - p + k - k/4) % 30
+	return M, (19*a + M) % 30
+}
+
+// meeusJonesButcher calculates the day and month of Easter for a given year
+// using the algorithm published by Meeus, Jones and Butcher.
+func meeusJonesButcher(year int) (int, int) {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	easterMonth := (h + l - 7*m + 114) / 31
+	easterDay := ((h + l - 7*m + 114) % 31) + 1
+
+	return easterDay, easterMonth
+}
+
+// anonymousGregorian calculates the day and month of Easter for a given
+// year using the anonymous Gregorian algorithm in the form published by
+// New Scientist in 1961.
+func anonymousGregorian(year int) (int, int) {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	g := (8*b + 13) / 25
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 19*l) / 433
+	n := (h + l - 7*m + 90) / 25
+	p := (h + l - 7*m + 33*n + 19) % 32
+
+	return p, n
+}
+
+// orthodoxEaster calculates the day and month of Easter as observed by the
+// Orthodox churches. Easter is computed in the Julian calendar and then
+// converted to the Gregorian calendar.
+func orthodoxEaster(year int) (int, int) {
+	a := year % 4
+	b := year % 7
+	d := julianMoon(year)
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := ((d + e + 114) % 31) + 1
+	return julianToGregorian(year, month, day)
+}
+
+// julianMoon returns the days from the 21st of March to the paschal full
+// moon in the Julian calendar.
+func julianMoon(year int) int {
+	return (19*(year%19) + 15) % 30
+}
+
+// julianToGregorian converts a day in March or April of the Julian
+// calendar to the Gregorian calendar. The Julian calendar falls behind by
+// a day in every century year which isn't a Gregorian leap year.
+func julianToGregorian(year, month, day int) (int, int) {
+	gregorian := utcDate(year, month, day).AddDate(0, 0, year/100-year/400-2)
+	return gregorian.Day(), int(gregorian.Month())
+}
+
+// PaschalFullMoon returns the ecclesiastical full moon of the given year
+// which Easter is the following Sunday of. For Orthodox, this is the full
+// moon of the Julian computus, converted to the Gregorian calendar.
+func (m Method) PaschalFullMoon(year int) (time.Time, error) {
+	if _, ok := computus[m]; !ok {
+		return time.Time{}, fmt.Errorf("unknown method: %s", m)
+	}
+	if err := checkYear(year); err != nil {
+		return time.Time{}, err
+	}
+	if m == Orthodox {
+		day, month := julianToGregorian(year, 3, 21+julianMoon(year))
+		return utcDate(year, month, day), nil
+	}
+
+	// Gauss' exceptions move the full moon back by a day, so Easter stays
+	// on or before the 25th of April.
+	M, d := gaussMoon(year)
+	if d == 29 || (d == 28 && (11*M+11)%30 < 19) {
+		d--
+	}
+	return utcDate(year, 3, 21+d), nil
+}
+
+// PaschalFullMoon returns the Gregorian paschal full moon of the given
+// year.
+func PaschalFullMoon(year int) (time.Time, error) {
+	return Gauss.PaschalFullMoon(year)
+}
+
+// SelfCheck cross-validates the methods for the years from to to. The
+// Gregorian methods must agree, every Easter must be a Sunday in the range
+// its calendar allows and the Sunday after its paschal full moon. It
+// returns the discrepancies found.
+func SelfCheck(from, to int) []error {
+	var problems []error
+	for year := from; year <= to; year++ {
+		if err := checkYear(year); err != nil {
+			return append(problems, err)
+		}
+		gauss, _ := Gauss.Easter(year)
+		for _, m := range []Method{Meeus, Anonymous} {
+			if other, _ := m.Easter(year); !other.Equal(gauss) {
+				problems = append(problems, fmt.Errorf("%d: gauss gives %s, %s gives %s", year, gauss.Format("2006-01-02"), m, other.Format("2006-01-02")))
+			}
+		}
+
+		// The Julian range shifts by the difference of the calendars.
+		orthodox, _ := Orthodox.Easter(year)
+		for _, c := range []struct {
+			m      Method
+			easter time.Time
+			shift  int
+		}{{Gauss, gauss, 0}, {Orthodox, orthodox, year/100 - year/400 - 2}} {
+			if err := checkSunday(c.m, c.easter, c.shift); err != nil {
+				problems = append(problems, err)
+			}
+			moon, _ := c.m.PaschalFullMoon(year)
+			if days := int(c.easter.Sub(moon).Hours() / 24); days < 1 || days > 7 {
+				problems = append(problems, fmt.Errorf("%d: %s Easter %s is not the Sunday after the paschal full moon %s", year, c.m, c.easter.Format("2006-01-02"), moon.Format("Mon 2006-01-02")))
+			}
+		}
+		if orthodox.Before(gauss) {
+			problems = append(problems, fmt.Errorf("%d: orthodox Easter %s is before Gregorian Easter %s", year, orthodox.Format("2006-01-02"), gauss.Format("2006-01-02")))
+		}
+	}
+	return problems
+}
+
+// checkSunday checks that Easter is a Sunday from the 22nd of March to the
+// 25th of April, shifted by shift days.
+func checkSunday(m Method, easter time.Time, shift int) error {
+	low := utcDate(easter.Year(), 3, 22+shift)
+	high := utcDate(easter.Year(), 4, 25+shift)
+	if easter.Weekday() != time.Sunday || easter.Before(low) || easter.After(high) {
+		return fmt.Errorf("%d: %s Easter %s is not a Sunday from %s to %s", easter.Year(), m, easter.Format("Mon 2006-01-02"), low.Format("2006-01-02"), high.Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package easter calculates the date of Easter, the movable feasts and the
+// public holidays which depend on it, and counts working days.
+//
+//	sunday, err := easter.Easter(2025)
+//	if err != nil {
+//		return err
+//	}
+//	holidays, err := easter.Holidays("DE-BY", 2025)
+//
+// All dates are midnight UTC. The command line tool is in the cmd/easter
+// directory.
+package easter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The algorithms only hold for the years of the Gregorian calendar up to
+// MaxYear, beyond that the Gauss formula needs further adjustments.
+const (
+	MinYear = 1583
+	MaxYear = 4099
+)
+
+// ErrYearOutOfRange is returned for years outside of MinYear to MaxYear.
+var ErrYearOutOfRange = errors.New("year out of range")
+
+func checkYear(year int) error {
+	if year < MinYear || year > MaxYear {
+		return fmt.Errorf("%w: %d is not within %d to %d", ErrYearOutOfRange, year, MinYear, MaxYear)
+	}
+	return nil
+}
+
+// Method is an algorithm calculating the date of Easter.
+type Method string
+
+const (
+	// Gauss is the Gauss Easter formula.
+	Gauss Method = "gauss"
+	// Meeus is the algorithm published by Meeus, Jones and Butcher.
+	Meeus Method = "meeus"
+	// Anonymous is the anonymous Gregorian algorithm in the form published
+	// by New Scientist in 1961.
+	Anonymous Method = "anonymous"
+	// Orthodox is Easter as observed by the Orthodox churches, calculated
+	// in the Julian calendar and converted to the Gregorian calendar.
+	Orthodox Method = "orthodox"
+)
+
+// computus returns the day and month of Easter Sunday in the Gregorian
+// calendar for each method.
+var computus = map[Method]func(year int) (int, int){
+	Gauss:     gaussEasterFormula,
+	Meeus:     meeusJonesButcher,
+	Anonymous: anonymousGregorian,
+	Orthodox:  orthodoxEaster,
+}
+
+// ParseMethod returns the method with the given name. julian is another
+// name for orthodox.
+func ParseMethod(name string) (Method, error) {
+	m := Method(strings.ToLower(name))
+	if m == "julian" {
+		return Orthodox, nil
+	}
+	if _, ok := computus[m]; !ok {
+		return "", fmt.Errorf("unknown method: %s (use %s)", name, strings.Join(MethodNames(), ", "))
+	}
+	return m, nil
+}
+
+// MethodNames returns the names of all methods, sorted.
+func MethodNames() []string {
+	names := []string{"julian"}
+	for m := range computus {
+		names = append(names, string(m))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Easter returns Easter Sunday of the given year.
+func (m Method) Easter(year int) (time.Time, error) {
+	compute, ok := computus[m]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown method: %s", m)
+	}
+	if err := checkYear(year); err != nil {
+		return time.Time{}, err
+	}
+	day, month := compute(year)
+	return utcDate(year, month, day), nil
+}
+
+// Easter returns Easter Sunday of the given year using the Gauss Easter
+// formula.
+func Easter(year int) (time.Time, error) {
+	return Gauss.Easter(year)
+}
+
+// GaussEasterFormula calculates the date of Easter for a given year using
+// the Gauss Easter formula.
+func GaussEasterFormula(year int) (time.Time, error) {
+	return Gauss.Easter(year)
+}
+
+// utcDate returns midnight UTC of the given day.
+func utcDate(year, month, day int) time.Time {
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// ISOWeek returns the ISO 8601 year and week number of t.
+func ISOWeek(t time.Time) (year, week int) {
+	return t.ISOWeek()
+}
+
+// DayOfYear returns the day of the year of t, from 1 to 366.
+func DayOfYear(t time.Time) int {
+	return t.YearDay()
+}
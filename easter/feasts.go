@@ -0,0 +1,63 @@
+package easter
+
+import "time"
+
+// Feast is a named feast day.
+type Feast struct {
+	Name string
+	Date time.Time
+}
+
+// easterOffsets are the movable feasts in days relative to Easter Sunday.
+var easterOffsets = []struct {
+	name   string
+	offset int
+}{
+	{"Ash Wednesday", -46},
+	{"Palm Sunday", -7},
+	{"Maundy Thursday", -3},
+	{"Good Friday", -2},
+	{"Holy Saturday", -1},
+	{"Easter Sunday", 0},
+	{"Easter Monday", 1},
+	{"Ascension Day", 39},
+	{"Pentecost", 49},
+	{"Whit Monday", 50},
+	{"Trinity Sunday", 56},
+	{"Corpus Christi", 60},
+}
+
+// MovableFeasts returns the feasts which depend on the date of Easter and
+// the four Sundays of Advent of the given year, in date order.
+func MovableFeasts(year int) ([]Feast, error) {
+	return Gauss.MovableFeasts(year)
+}
+
+// MovableFeasts returns the movable feasts of the given year with Easter
+// calculated by m.
+func (m Method) MovableFeasts(year int) ([]Feast, error) {
+	easter, err := m.Easter(year)
+	if err != nil {
+		return nil, err
+	}
+	feasts := make([]Feast, 0, len(easterOffsets)+4)
+	for _, f := range easterOffsets {
+		feasts = append(feasts, Feast{Name: f.name, Date: easter.AddDate(0, 0, f.offset)})
+	}
+
+	for i, name := range []string{"First Sunday of Advent", "Second Sunday of Advent", "Third Sunday of Advent", "Fourth Sunday of Advent"} {
+		feasts = append(feasts, Feast{Name: name, Date: AdventSunday(year, i+1)})
+	}
+	return feasts, nil
+}
+
+// AdventSunday returns the n-th Sunday of Advent (1 to 4) of the given
+// year. The fourth Sunday of Advent is the last Sunday before Christmas.
+func AdventSunday(year, n int) time.Time {
+	christmas := utcDate(year, 12, 25)
+	back := int(christmas.Weekday())
+	if back == 0 {
+		back = 7
+	}
+	return christmas.AddDate(0, 0, -back-7*(4-n))
+}
@@ -0,0 +1,305 @@
+package easter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Holiday is a public holiday of a region.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// holidayRule is a line of the holiday table. The date is one of
+//
+//	12-25        a fixed day and month
+//	easter+39    days relative to Easter Sunday
+//	wed<11-23    the last weekday before a fixed day
+//	3sun/09+1    the n-th weekday of a month, plus an offset
+//
+// regions lists ISO 3166-2 codes separated by commas. A country code like
+// DE covers all of its subdivisions, a !-prefixed code excludes one again.
+// since and until limit the years a holiday exists, 0 means no limit.
+type holidayRule struct {
+	name         string
+	date         string
+	regions      string
+	since, until int
+}
+
+var holidayTable = []holidayRule{
+	// Germany
+	{name: "Neujahr", date: "01-01", regions: "DE"},
+	{name: "Heilige Drei Könige", date: "01-06", regions: "DE-BW, DE-BY, DE-ST"},
+	{name: "Internationaler Frauentag", date: "03-08", regions: "DE-BE", since: 2019},
+	{name: "Internationaler Frauentag", date: "03-08", regions: "DE-MV", since: 2023},
+	{name: "Karfreitag", date: "easter-2", regions: "DE"},
+	{name: "Ostersonntag", date: "easter", regions: "DE-BB"},
+	{name: "Ostermontag", date: "easter+1", regions: "DE"},
+	{name: "Tag der Arbeit", date: "05-01", regions: "DE"},
+	{name: "Christi Himmelfahrt", date: "easter+39", regions: "DE"},
+	{name: "Pfingstsonntag", date: "easter+49", regions: "DE-BB"},
+	{name: "Pfingstmontag", date: "easter+50", regions: "DE"},
+	{name: "Fronleichnam", date: "easter+60", regions: "DE-BW, DE-BY, DE-HE, DE-NW, DE-RP, DE-SL"},
+	// In Bavaria only in the communities with a Catholic majority.
+	{name: "Mariä Himmelfahrt", date: "08-15", regions: "DE-BY, DE-SL"},
+	{name: "Weltkindertag", date: "09-20", regions: "DE-TH", since: 2019},
+	{name: "Tag der Deutschen Einheit", date: "10-03", regions: "DE", since: 1990},
+	{name: "Reformationstag", date: "10-31", regions: "DE-BB, DE-MV, DE-SN, DE-ST, DE-TH", since: 1990},
+	{name: "Reformationstag", date: "10-31", regions: "DE-HB, DE-HH, DE-NI, DE-SH", since: 2018},
+	{name: "Reformationstag", date: "10-31", regions: "DE", since: 2017, until: 2017},
+	{name: "Allerheiligen", date: "11-01", regions: "DE-BW, DE-BY, DE-NW, DE-RP, DE-SL"},
+	{name: "Buß- und Bettag", date: "wed<11-23", regions: "DE", until: 1994},
+	{name: "Buß- und Bettag", date: "wed<11-23", regions: "DE-SN", since: 1995},
+	{name: "1. Weihnachtstag", date: "12-25", regions: "DE"},
+	{name: "2. Weihnachtstag", date: "12-26", regions: "DE"},
+
+	// Austria
+	{name: "Neujahr", date: "01-01", regions: "AT"},
+	{name: "Heilige Drei Könige", date: "01-06", regions: "AT"},
+	{name: "Ostermontag", date: "easter+1", regions: "AT"},
+	{name: "Staatsfeiertag", date: "05-01", regions: "AT"},
+	{name: "Christi Himmelfahrt", date: "easter+39", regions: "AT"},
+	{name: "Pfingstmontag", date: "easter+50", regions: "AT"},
+	{name: "Fronleichnam", date: "easter+60", regions: "AT"},
+	{name: "Mariä Himmelfahrt", date: "08-15", regions: "AT"},
+	{name: "Nationalfeiertag", date: "10-26", regions: "AT", since: 1965},
+	{name: "Allerheiligen", date: "11-01", regions: "AT"},
+	{name: "Mariä Empfängnis", date: "12-08", regions: "AT"},
+	{name: "Christtag", date: "12-25", regions: "AT"},
+	{name: "Stefanitag", date: "12-26", regions: "AT"},
+
+	// Switzerland
+	{name: "Neujahrstag", date: "01-01", regions: "CH"},
+	{name: "Berchtoldstag", date: "01-02", regions: "CH-AG, CH-BE, CH-FR, CH-GL, CH-JU, CH-LU, CH-NE, CH-OW, CH-SH, CH-SO, CH-TG, CH-VD, CH-ZG, CH-ZH"},
+	{name: "Heilige Drei Könige", date: "01-06", regions: "CH-SZ, CH-TI, CH-UR"},
+	{name: "Jahrestag der Ausrufung der Republik", date: "03-01", regions: "CH-NE"},
+	{name: "Josefstag", date: "03-19", regions: "CH-NW, CH-SZ, CH-TI, CH-UR, CH-VS"},
+	{name: "Karfreitag", date: "easter-2", regions: "CH, !CH-TI, !CH-VS"},
+	{name: "Ostermontag", date: "easter+1", regions: "CH, !CH-VS"},
+	{name: "Tag der Arbeit", date: "05-01", regions: "CH-BL, CH-BS, CH-JU, CH-NE, CH-SH, CH-SO, CH-TG, CH-TI, CH-ZH"},
+	{name: "Auffahrt", date: "easter+39", regions: "CH"},
+	{name: "Pfingstmontag", date: "easter+50", regions: "CH, !CH-VS"},
+	{name: "Fronleichnam", date: "easter+60", regions: "CH-AG, CH-AI, CH-FR, CH-JU, CH-LU, CH-NW, CH-OW, CH-SO, CH-SZ, CH-TI, CH-UR, CH-VS, CH-ZG"},
+	{name: "Fest der Unabhängigkeit", date: "06-23", regions: "CH-JU"},
+	{name: "Peter und Paul", date: "06-29", regions: "CH-TI"},
+	{name: "Bundesfeiertag", date: "08-01", regions: "CH", since: 1994},
+	{name: "Mariä Himmelfahrt", date: "08-15", regions: "CH-AG, CH-AI, CH-FR, CH-JU, CH-LU, CH-NW, CH-OW, CH-SO, CH-SZ, CH-TI, CH-UR, CH-VS, CH-ZG"},
+	{name: "Jeûne genevois", date: "1sun/09+4", regions: "CH-GE"},
+	{name: "Lundi du Jeûne", date: "3sun/09+1", regions: "CH-VD"},
+	{name: "Bruder Klaus", date: "09-25", regions: "CH-OW"},
+	{name: "Allerheiligen", date: "11-01", regions: "CH-AG, CH-AI, CH-FR, CH-GL, CH-JU, CH-LU, CH-NW, CH-OW, CH-SG, CH-SO, CH-SZ, CH-TI, CH-UR, CH-VS, CH-ZG"},
+	{name: "Mariä Empfängnis", date: "12-08", regions: "CH-AG, CH-AI, CH-FR, CH-LU, CH-NW, CH-OW, CH-SZ, CH-TI, CH-UR, CH-VS, CH-ZG"},
+	{name: "Weihnachtstag", date: "12-25", regions: "CH"},
+	{name: "Stephanstag", date: "12-26", regions: "CH, !CH-GE, !CH-JU, !CH-NE, !CH-VD, !CH-VS"},
+	{name: "Restauration de la République", date: "12-31", regions: "CH-GE"},
+}
+
+// Regions are the regions with holidays and their names.
+var Regions = map[string]string{
+	"DE":    "Deutschland",
+	"DE-BB": "Brandenburg",
+	"DE-BE": "Berlin",
+	"DE-BW": "Baden-Württemberg",
+	"DE-BY": "Bayern",
+	"DE-HB": "Bremen",
+	"DE-HE": "Hessen",
+	"DE-HH": "Hamburg",
+	"DE-MV": "Mecklenburg-Vorpommern",
+	"DE-NI": "Niedersachsen",
+	"DE-NW": "Nordrhein-Westfalen",
+	"DE-RP": "Rheinland-Pfalz",
+	"DE-SH": "Schleswig-Holstein",
+	"DE-SL": "Saarland",
+	"DE-SN": "Sachsen",
+	"DE-ST": "Sachsen-Anhalt",
+	"DE-TH": "Thüringen",
+
+	"AT":   "Österreich",
+	"AT-1": "Burgenland",
+	"AT-2": "Kärnten",
+	"AT-3": "Niederösterreich",
+	"AT-4": "Oberösterreich",
+	"AT-5": "Salzburg",
+	"AT-6": "Steiermark",
+	"AT-7": "Tirol",
+	"AT-8": "Vorarlberg",
+	"AT-9": "Wien",
+
+	"CH":    "Schweiz",
+	"CH-AG": "Aargau",
+	"CH-AI": "Appenzell Innerrhoden",
+	"CH-AR": "Appenzell Ausserrhoden",
+	"CH-BE": "Bern",
+	"CH-BL": "Basel-Landschaft",
+	"CH-BS": "Basel-Stadt",
+	"CH-FR": "Fribourg",
+	"CH-GE": "Genève",
+	"CH-GL": "Glarus",
+	"CH-GR": "Graubünden",
+	"CH-JU": "Jura",
+	"CH-LU": "Luzern",
+	"CH-NE": "Neuchâtel",
+	"CH-NW": "Nidwalden",
+	"CH-OW": "Obwalden",
+	"CH-SG": "St. Gallen",
+	"CH-SH": "Schaffhausen",
+	"CH-SO": "Solothurn",
+	"CH-SZ": "Schwyz",
+	"CH-TG": "Thurgau",
+	"CH-TI": "Ticino",
+	"CH-UR": "Uri",
+	"CH-VD": "Vaud",
+	"CH-VS": "Valais",
+	"CH-ZG": "Zug",
+	"CH-ZH": "Zürich",
+}
+
+// compiledRule is a holiday rule with its date function and parsed regions.
+type compiledRule struct {
+	holidayRule
+	dateOf  func(year int) time.Time
+	include []string
+	exclude []string
+}
+
+var compiledRules = compileRules(holidayTable)
+
+func compileRules(table []holidayRule) []compiledRule {
+	rules := make([]compiledRule, 0, len(table))
+	for _, r := range table {
+		dateOf, err := parseDateRule(r.date)
+		if err != nil {
+			panic(fmt.Sprintf("holiday %s: %v", r.name, err))
+		}
+		c := compiledRule{holidayRule: r, dateOf: dateOf}
+		for _, region := range strings.Split(r.regions, ",") {
+			region = strings.TrimSpace(region)
+			if name, ok := strings.CutPrefix(region, "!"); ok {
+				c.exclude = append(c.exclude, name)
+			} else {
+				c.include = append(c.include, region)
+			}
+		}
+		rules = append(rules, c)
+	}
+	return rules
+}
+
+var dateRulePattern = regexp.MustCompile(`^(?:(\d\d)-(\d\d)|easter|([a-z]{3})<(\d\d)-(\d\d)|([1-5])([a-z]{3})/(\d\d))([+-]\d+)?$`)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseDateRule parses the date of a holiday rule into a function
+// returning the date in a given year.
+func parseDateRule(rule string) (func(year int) time.Time, error) {
+	m := dateRulePattern.FindStringSubmatch(rule)
+	if m == nil {
+		return nil, fmt.Errorf("invalid date rule %q", rule)
+	}
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	offset := atoi(m[9])
+
+	var base func(year int) time.Time
+	switch {
+	case m[1] != "":
+		month, day := atoi(m[1]), atoi(m[2])
+		base = func(year int) time.Time { return utcDate(year, month, day) }
+	case m[3] != "":
+		wd, ok := weekdays[m[3]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday in date rule %q", rule)
+		}
+		month, day := atoi(m[4]), atoi(m[5])
+		base = func(year int) time.Time {
+			before := utcDate(year, month, day)
+			back := (int(before.Weekday())-int(wd)+6)%7 + 1
+			return before.AddDate(0, 0, -back)
+		}
+	case m[6] != "":
+		wd, ok := weekdays[m[7]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday in date rule %q", rule)
+		}
+		n, month := atoi(m[6]), atoi(m[8])
+		base = func(year int) time.Time {
+			first := utcDate(year, month, 1)
+			ahead := (int(wd) - int(first.Weekday()) + 7) % 7
+			return first.AddDate(0, 0, ahead+7*(n-1))
+		}
+	default:
+		base = func(year int) time.Time {
+			day, month := gaussEasterFormula(year)
+			return utcDate(year, month, day)
+		}
+	}
+	return func(year int) time.Time { return base(year).AddDate(0, 0, offset) }, nil
+}
+
+// appliesTo reports whether the rule applies to region in year.
+func (r compiledRule) appliesTo(region string, year int) bool {
+	if (r.since != 0 && year < r.since) || (r.until != 0 && year > r.until) {
+		return false
+	}
+	for _, e := range r.exclude {
+		if region == e {
+			return false
+		}
+	}
+	for _, i := range r.include {
+		if region == i || strings.HasPrefix(region, i+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// Holidays returns the public holidays of region in year, sorted by date.
+// A country code like DE returns only the holidays of the whole country.
+func Holidays(region string, year int) ([]Holiday, error) {
+	region = strings.ToUpper(region)
+	if _, ok := Regions[region]; !ok {
+		return nil, fmt.Errorf("unknown region: %s", region)
+	}
+	if err := checkYear(year); err != nil {
+		return nil, err
+	}
+
+	var holidays []Holiday
+	seen := make(map[Holiday]bool)
+	for _, r := range compiledRules {
+		if !r.appliesTo(region, year) {
+			continue
+		}
+		h := Holiday{Date: r.dateOf(year), Name: r.name}
+		if !seen[h] {
+			seen[h] = true
+			holidays = append(holidays, h)
+		}
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays, nil
+}
+
+// RegionCodes returns the codes of all regions, sorted.
+func RegionCodes() []string {
+	codes := make([]string, 0, len(Regions))
+	for code := range Regions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
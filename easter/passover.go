@@ -0,0 +1,54 @@
+package easter
+
+import "time"
+
+// Passover returns the first day of Passover (Pesach), the 15th of Nisan,
+// in the given Gregorian year. The feast begins at sunset of the day
+// before.
+func Passover(year int) (time.Time, error) {
+	if err := checkYear(year); err != nil {
+		return time.Time{}, err
+	}
+	// Rosh Hashanah of the Hebrew year beginning in the autumn is always
+	// 163 days after the 15th of Nisan.
+	return hebrewNewYear(year+3761).AddDate(0, 0, -163), nil
+}
+
+// hebrewEpoch is the 1st of Tishri of the year 1 of the Hebrew calendar,
+// the 7th of October 3761 BCE in the proleptic Julian calendar, as days
+// since the 1st of January of the year 1 of the proleptic Gregorian
+// calendar.
+const hebrewEpoch = -1373428
+
+// hebrewNewYear returns Rosh Hashanah, the 1st of Tishri, of a Hebrew year.
+func hebrewNewYear(year int) time.Time {
+	days := hebrewEpoch + hebrewElapsedDays(year) + hebrewYearDelay(year)
+	return time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+}
+
+// hebrewElapsedDays returns the days from the epoch to the molad of Tishri
+// of year, postponed if it falls on a Sunday, Wednesday or Friday.
+func hebrewElapsedDays(year int) int {
+	months := (235*year - 234) / 19
+	parts := 12084 + 13753*months
+	days := 29*months + parts/25920
+	if (3*(days+1))%7 < 3 {
+		days++
+	}
+	return days
+}
+
+// hebrewYearDelay postpones the new year once more if the year or the
+// previous one would get an invalid length.
+func hebrewYearDelay(year int) int {
+	last := hebrewElapsedDays(year - 1)
+	this := hebrewElapsedDays(year)
+	next := hebrewElapsedDays(year + 1)
+	switch {
+	case next-this == 356:
+		return 2
+	case this-last == 382:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package easter
+
+import "time"
+
+// WorkCalendar tells working days from weekends and the public holidays of
+// a region. It caches the holidays of each year, so it isn't safe for
+// concurrent use.
+type WorkCalendar struct {
+	region   string
+	holidays map[int]map[string]string
+}
+
+// NewWorkCalendar returns the working day calendar of region. Without a
+// region, only Saturdays and Sundays are days off.
+func NewWorkCalendar(region string) (*WorkCalendar, error) {
+	if region != "" {
+		if _, err := Holidays(region, 2000); err != nil {
+			return nil, err
+		}
+	}
+	return &WorkCalendar{region: region, holidays: make(map[int]map[string]string)}, nil
+}
+
+// Holiday returns the name of the public holiday on day, if it is one.
+// Outside of MinYear to MaxYear, no day is a holiday.
+func (c *WorkCalendar) Holiday(day time.Time) (string, bool) {
+	if c.region == "" {
+		return "", false
+	}
+	year, ok := c.holidays[day.Year()]
+	if !ok {
+		year = make(map[string]string)
+		holidays, _ := Holidays(c.region, day.Year())
+		for _, h := range holidays {
+			year[h.Date.Format("2006-01-02")] = h.Name
+		}
+		c.holidays[day.Year()] = year
+	}
+	name, ok := year[day.Format("2006-01-02")]
+	return name, ok
+}
+
+// IsWorkday reports whether day is neither on a weekend nor a holiday.
+func (c *WorkCalendar) IsWorkday(day time.Time) bool {
+	if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	_, holiday := c.Holiday(day)
+	return !holiday
+}
+
+// Workdays counts the working days from start to end, both included.
+func (c *WorkCalendar) Workdays(start, end time.Time) int {
+	n := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkday(day) {
+			n++
+		}
+	}
+	return n
+}
+
+// AddWorkdays moves day by n working days, backwards if n is negative.
+func (c *WorkCalendar) AddWorkdays(day time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		day = day.AddDate(0, 0, step)
+		if c.IsWorkday(day) {
+			n--
+		}
+	}
+	return day
+}
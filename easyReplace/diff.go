@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ANSI escape codes of the colored diff.
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// fileDiff is the unified diff of the edits of a text, split into hunks.
+type fileDiff struct {
+	lines  []string
+	starts []int
+	hunks  []hunk
+}
+
+// change is a group of edits on the same or adjacent lines, first and last
+// are the indexes of the first and last old line.
+type change struct {
+	edits       []Edit
+	first, last int
+	newLines    []string
+}
+
+// hunk is a group of changes with lines of context, first and last are
+// the indexes of the first and last old line shown.
+type hunk struct {
+	changes     []change
+	first, last int
+	// delta is how many lines the changes before the hunk added.
+	delta int
+}
+
+// newFileDiff splits the edits of text into hunks with the given number
+// of lines of context.
+func newFileDiff(text string, edits []Edit, context int) *fileDiff {
+	d := &fileDiff{lines: strings.SplitAfter(text, "\n")}
+	if n := len(d.lines); n > 1 && d.lines[n-1] == "" {
+		d.lines = d.lines[:n-1]
+	}
+	offset := 0
+	for _, line := range d.lines {
+		d.starts = append(d.starts, offset)
+		offset += len(line)
+	}
+
+	var changes []change
+	for _, e := range edits {
+		first, last := d.lineOf(e.Start), d.lineOf(e.End)
+		if n := len(changes); n > 0 && first <= changes[n-1].last+1 {
+			changes[n-1].edits = append(changes[n-1].edits, e)
+			changes[n-1].last = max(changes[n-1].last, last)
+			continue
+		}
+		changes = append(changes, change{edits: []Edit{e}, first: first, last: last})
+	}
+
+	delta := 0
+	for i := range changes {
+		c := &changes[i]
+		start := d.starts[c.first]
+		end := d.starts[c.last] + len(d.lines[c.last])
+		shifted := make([]Edit, len(c.edits))
+		for j, e := range c.edits {
+			shifted[j] = Edit{Start: e.Start - start, End: e.End - start, New: e.New}
+		}
+		c.newLines = strings.SplitAfter(Apply(text[start:end], shifted), "\n")
+		if n := len(c.newLines); c.newLines[n-1] == "" {
+			c.newLines = c.newLines[:n-1]
+		}
+
+		if n := len(d.hunks); n > 0 && c.first-d.hunks[n-1].last <= context+1 {
+			h := &d.hunks[n-1]
+			h.changes = append(h.changes, *c)
+			h.last = min(c.last+context, len(d.lines)-1)
+		} else {
+			d.hunks = append(d.hunks, hunk{
+				changes: []change{*c},
+				first:   max(c.first-context, 0),
+				last:    min(c.last+context, len(d.lines)-1),
+				delta:   delta,
+			})
+		}
+		delta += len(c.newLines) - d.oldCount(c.first, c.last)
+	}
+	return d
+}
+
+// lineOf returns the index of the line containing the byte at offset.
+func (d *fileDiff) lineOf(offset int) int {
+	i := sort.Search(len(d.starts), func(i int) bool { return d.starts[i] > offset }) - 1
+	return min(max(i, 0), len(d.lines)-1)
+}
+
+// oldCount returns the number of old lines from first to last. The only
+// line of an empty text doesn't count.
+func (d *fileDiff) oldCount(first, last int) int {
+	if len(d.lines) == 1 && d.lines[0] == "" {
+		return 0
+	}
+	return last - first + 1
+}
+
+// write writes the diff of a file, with a header naming it and counting
+// the matches.
+func (d *fileDiff) write(w io.Writer, name string, matches int, color bool) {
+	d.writeHeader(w, name, matches, color)
+	for _, h := range d.hunks {
+		d.writeHunk(w, h, color)
+	}
+}
+
+func (d *fileDiff) writeHeader(w io.Writer, name string, matches int, color bool) {
+	paint(w, color, colorBold, fmt.Sprintf("%s: %s\n", name, plural(matches, "match", "matches")))
+	paint(w, color, colorBold, "--- "+name+"\n")
+	paint(w, color, colorBold, "+++ "+name+"\n")
+}
+
+func (d *fileDiff) writeHunk(w io.Writer, h hunk, color bool) {
+	oldCount := d.oldCount(h.first, h.last)
+	newCount := oldCount
+	for _, c := range h.changes {
+		newCount += len(c.newLines) - d.oldCount(c.first, c.last)
+	}
+	paint(w, color, colorCyan, fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(h.first+1, oldCount), hunkRange(h.first+1+h.delta, newCount)))
+
+	line := h.first
+	for _, c := range h.changes {
+		for ; line < c.first; line++ {
+			d.writeLine(w, " ", d.lines[line], "", color)
+		}
+		for ; line <= c.last; line++ {
+			if d.oldCount(line, line) > 0 {
+				d.writeLine(w, "-", d.lines[line], colorRed, color)
+			}
+		}
+		for _, l := range c.newLines {
+			d.writeLine(w, "+", l, colorGreen, color)
+		}
+	}
+	for ; line <= h.last; line++ {
+		d.writeLine(w, " ", d.lines[line], "", color)
+	}
+}
+
+// writeLine writes a line of a hunk and marks a missing newline at the
+// end of the file.
+func (d *fileDiff) writeLine(w io.Writer, prefix, line, code string, color bool) {
+	content, hasNewline := strings.CutSuffix(line, "\n")
+	paint(w, color && code != "", code, prefix+content)
+	io.WriteString(w, "\n")
+	if !hasNewline {
+		io.WriteString(w, "\\ No newline at end of file\n")
+	}
+}
+
+// hunkRange formats the start and length of a hunk. An empty range starts
+// at the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// paint writes s, in the color of code if color is set.
+func paint(w io.Writer, color bool, code, s string) {
+	if !color {
+		io.WriteString(w, s)
+		return
+	}
+	// Reset before the newline, so a pager doesn't carry the color on.
+	s, newline := strings.CutSuffix(s, "\n")
+	io.WriteString(w, code+s+colorReset)
+	if newline {
+		io.WriteString(w, "\n")
+	}
+}
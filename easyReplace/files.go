@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// defaultExcludes are the directories never searched with -r.
+var defaultExcludes = []string{".git/", ".hg/", ".svn/"}
+
+// backupSuffix is appended to the name of a backup, tempMarker is part of
+// the names of the temporary files. Neither is edited with -r.
+const (
+	backupSuffix = ".bak"
+	tempMarker   = ".easyReplace-tmp"
+)
+
+// fileSet selects the files to edit.
+type fileSet struct {
+	recursive bool
+	include   []string
+	exclude   []string
+}
+
+// collect returns the files below paths, without duplicates. Directories
+// are only searched with -r.
+func (fsel fileSet) collect(paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			// The file behind a symlink is edited, not the link replaced.
+			file, err := filepath.EvalSymlinks(root)
+			if err != nil {
+				return nil, err
+			}
+			if fsel.accept(filepath.Base(root), false) {
+				add(file)
+			}
+			continue
+		}
+		if !fsel.recursive {
+			return nil, fmt.Errorf("%s is a directory, use -r to search it", root)
+		}
+
+		err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if p == root {
+				return nil
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			if !fsel.accept(filepath.ToSlash(rel), d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			name := d.Name()
+			if d.Type().IsRegular() && !strings.HasSuffix(name, backupSuffix) && !strings.Contains(name, tempMarker) {
+				add(filepath.Clean(p))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// accept reports whether the file or directory with the slash separated
+// path rel is edited or searched.
+func (fsel fileSet) accept(rel string, isDir bool) bool {
+	if isDir && matchAny(defaultExcludes, rel, true) {
+		return false
+	}
+	if matchAny(fsel.exclude, rel, isDir) {
+		return false
+	}
+	return isDir || len(fsel.include) == 0 || matchAny(fsel.include, rel, false)
+}
+
+// matchAny reports whether rel matches one of the globs. A glob without a
+// slash matches the base name, one ending with a slash only directories.
+func matchAny(globs []string, rel string, isDir bool) bool {
+	for _, glob := range globs {
+		dirOnly := strings.HasSuffix(glob, "/")
+		glob = strings.TrimSuffix(glob, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+		name := rel
+		if !strings.Contains(glob, "/") {
+			name = path.Base(rel)
+		}
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// result is the outcome of editing a file.
+type result struct {
+	file     string
+	matches  int
+	replaced int
+	diff     string
+	err      error
+}
+
+// editor replaces in files in place.
+type editor struct {
+	replacer *Replacer
+	backup   bool
+	// dryRun only renders the diff of every file with a match, with
+	// context lines of context and colored if color is set.
+	dryRun  bool
+	context int
+	color   bool
+	// confirm, if set, selects the edits to apply. It is called from the
+	// workers, so it needs a single worker.
+	confirm func(name, text string, edits []Edit) []Edit
+}
+
+// editAll edits the files with the given number of workers and returns the
+// results in the order of the files.
+func (e *editor) editAll(files []string, workers int) []result {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]result, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = e.edit(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// edit replaces in a file. Binary files, which contain a NUL byte, and
+// files without a match are left alone.
+func (e *editor) edit(file string) result {
+	res := result{file: file}
+	info, err := os.Stat(file)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return res
+	}
+
+	text := string(data)
+	edits := e.selectEdits(&res, text)
+	if len(edits) == 0 {
+		return res
+	}
+	if e.backup {
+		if res.err = writeBackup(file+backupSuffix, data, info.Mode().Perm()); res.err != nil {
+			return res
+		}
+	}
+	res.err = writeAtomic(file, []byte(Apply(text, edits)), info.Mode().Perm())
+	if res.err == nil {
+		res.replaced = len(edits)
+	}
+	return res
+}
+
+// selectEdits returns the edits of text to apply and counts the matches.
+// In a dry run, it renders the diff and returns no edits.
+func (e *editor) selectEdits(res *result, text string) []Edit {
+	edits := e.replacer.Edits(text)
+	res.matches = len(edits)
+	switch {
+	case len(edits) == 0:
+		return nil
+	case e.dryRun:
+		var b strings.Builder
+		newFileDiff(text, edits, e.context).write(&b, res.file, len(edits), e.color)
+		res.diff = b.String()
+		return nil
+	case e.confirm != nil:
+		return e.confirm(res.file, text, edits)
+	}
+	return edits
+}
+
+// writeAtomic writes data to a temporary file next to file and renames it
+// over file, so file is never left half written.
+func writeAtomic(file string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+tempMarker+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// writeBackup writes the backup of a file. An existing backup is never
+// overwritten, so the original of an earlier run isn't lost.
+func writeBackup(file string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("backup %s already exists", file)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(file)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(file)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prompter asks for every hunk whether to apply it, like git add -p.
+type prompter struct {
+	in      *bufio.Reader
+	out     io.Writer
+	context int
+	color   bool
+	quit    bool
+}
+
+const promptHelp = `y - apply this hunk
+n - do not apply this hunk
+a - apply this hunk and all later hunks in the file
+q - quit; do not apply this hunk or any of the remaining ones
+? - print help
+`
+
+// confirm shows the hunks of the edits of text and returns the edits of
+// the hunks to apply. After q, it returns no edits for any further file.
+func (p *prompter) confirm(name, text string, edits []Edit) []Edit {
+	if p.quit {
+		return nil
+	}
+	d := newFileDiff(text, edits, p.context)
+	d.writeHeader(p.out, name, len(edits), p.color)
+
+	var accepted []Edit
+	all := false
+	for i, h := range d.hunks {
+		if !all {
+			d.writeHunk(p.out, h, p.color)
+			switch p.ask(fmt.Sprintf("(%d/%d) Apply this hunk to %s [y,n,a,q,?]? ", i+1, len(d.hunks), name)) {
+			case "n":
+				continue
+			case "a":
+				all = true
+			case "q":
+				p.quit = true
+				return accepted
+			}
+		}
+		for _, c := range h.changes {
+			accepted = append(accepted, c.edits...)
+		}
+	}
+	return accepted
+}
+
+// ask prompts until the answer is y, n, a or q. The end of the input
+// quits.
+func (p *prompter) ask(prompt string) string {
+	for {
+		paint(p.out, p.color, colorBold, prompt)
+		answer, err := p.in.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		switch {
+		case answer == "y" || answer == "n" || answer == "a" || answer == "q":
+			return answer
+		case err != nil:
+			fmt.Fprintln(p.out)
+			return "q"
+		}
+		paint(p.out, p.color, colorRed, promptHelp)
+	}
+}
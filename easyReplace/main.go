@@ -1,61 +1,212 @@
 // Description: A simple tool to replace a string in a file with another string.
+// With -regex, the search is a regular expression and $1 or ${name} in the replacement are expanded.
+// With -i, the files are edited in place, with -r whole directory trees.
+// With -dry-run, a diff of the replacements is shown instead, with -interactive every hunk is confirmed.
 package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
 )
 
 func main() {
-	if len(os.Args) != 5 {
-		fmt.Println("Usage: easyReplace <input_file> <output_file> <search> <replace>")
-		return
+	os.Exit(run())
+}
+
+func run() int {
+	var opts Options
+	var fsel fileSet
+	var inPlace, backup, dryRun, interactive bool
+	var workers, context int
+	var color string
+	flag.BoolVar(&opts.Regex, "regex", false, "Treat the search as a regular expression (RE2 syntax) and expand $1 and ${name} in the replacement")
+	flag.BoolVar(&opts.IgnoreCase, "ignore-case", false, "Match regardless of case")
+	flag.BoolVar(&opts.WholeWord, "w", false, "Only match whole words")
+	flag.BoolVar(&opts.Multiline, "multiline", false, "Match the whole file at once, so matches may span lines")
+	flag.BoolVar(&inPlace, "i", false, "Edit the files given after the search and replacement in place")
+	flag.BoolVar(&fsel.recursive, "r", false, "With -i, edit all files in the given directories")
+	flag.Var((*stringList)(&fsel.include), "include", "With -i, only edit files matching this glob (repeatable)")
+	flag.Var((*stringList)(&fsel.exclude), "exclude", "With -i, skip files and directories matching this glob, e.g. vendor/ (repeatable)")
+	flag.BoolVar(&backup, "backup", false, "With -i, keep the original of every changed file as <file>.bak")
+	flag.IntVar(&workers, "workers", runtime.NumCPU(), "With -i, number of files edited in parallel")
+	flag.BoolVar(&dryRun, "dry-run", false, "Don't write anything, print a unified diff of the replacements instead")
+	flag.BoolVar(&interactive, "interactive", false, "Ask for every hunk whether to apply it (y/n/a/q), like git add -p")
+	flag.IntVar(&context, "context", 3, "Number of lines of context in the diff")
+	flag.StringVar(&color, "color", "auto", "Color the diff: auto, always or never")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: easyReplace [flags] <input_file> <output_file> <search> <replace>")
+		fmt.Fprintln(flag.CommandLine.Output(), "       easyReplace -i [-r] [flags] <search> <replace> <paths...>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if dryRun && interactive {
+		fmt.Println("Error: -dry-run and -interactive can't be combined")
+		return 2
+	}
+	useColor, err := colorOutput(color)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return 2
+	}
+
+	var search, replace string
+	switch {
+	case inPlace && flag.NArg() >= 3:
+		search, replace = flag.Arg(0), flag.Arg(1)
+	case !inPlace && flag.NArg() == 4:
+		search, replace = flag.Arg(2), flag.Arg(3)
+	default:
+		flag.Usage()
+		return 2
+	}
+	replacer, err := NewReplacer(search, replace, opts)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return 2
 	}
 
-	inputFile := os.Args[1]
-	outputFile := os.Args[2]
-	search := os.Args[3]
-	replace := os.Args[4]
+	e := &editor{replacer: replacer, backup: backup, dryRun: dryRun, context: max(context, 0), color: useColor}
+	if interactive {
+		p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout, context: e.context, color: useColor}
+		e.confirm = p.confirm
+		// The hunks of one file are shown at a time.
+		workers = 1
+	}
+	if inPlace {
+		return runInPlace(e, fsel, flag.Args()[2:], workers)
+	}
+
+	inputFile := flag.Arg(0)
+	outputFile := flag.Arg(1)
 
-	file, err := os.Open(inputFile)
+	data, err := os.ReadFile(inputFile)
 	if err != nil {
-		fmt.Println("Error opening input file:", err)
-		return
+		fmt.Println("Error reading input file:", err)
+		return 1
+	}
+	text := string(data)
+	res := result{file: inputFile}
+	edits := e.selectEdits(&res, text)
+	if dryRun {
+		fmt.Print(res.diff)
+		report([]result{res}, true)
+		return 0
 	}
-	defer file.Close()
 
 	outFile, err := os.Create(outputFile)
 	if err != nil {
 		fmt.Println("Error creating output file:", err)
-		return
+		return 1
 	}
 	defer outFile.Close()
 
-	scanner := bufio.NewScanner(file)
-	writer := bufio.NewWriter(outFile)
+	if _, err := outFile.WriteString(Apply(text, edits)); err != nil {
+		fmt.Println("Error writing to output file:", err)
+		return 1
+	}
+	res.replaced = len(edits)
+	report([]result{res}, false)
+	return 0
+}
+
+// runInPlace replaces in the files and directories given by paths.
+func runInPlace(e *editor, fsel fileSet, paths []string, workers int) int {
+	files, err := fsel.collect(paths)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return 1
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		replacedLine := strings.ReplaceAll(line, search, replace)
-		_, err := writer.WriteString(replacedLine + "\n")
-		if err != nil {
-			fmt.Println("Error writing to output file:", err)
-			return
+	results := e.editAll(files, workers)
+	code := 0
+	for _, res := range results {
+		if res.err != nil {
+			fmt.Printf("Error editing file %s: %v\n", res.file, res.err)
+			code = 1
 		}
+		fmt.Print(res.diff)
 	}
+	report(results, e.dryRun)
+	return code
+}
 
-	err = writer.Flush()
-	if err != nil {
-		fmt.Println("Error flushing writer:", err)
-		return
+// report prints the number of matches replaced in every file and in
+// total, or the number which would be replaced in a dry run.
+func report(results []result, dryRun bool) {
+	var matches, replaced, files int
+	for _, res := range results {
+		matches += res.matches
+		replaced += res.replaced
+		if (dryRun && res.matches > 0) || res.replaced > 0 {
+			files++
+		}
+		if !dryRun && res.replaced > 0 {
+			if res.replaced < res.matches {
+				fmt.Printf("%s: replaced %d of %d matches\n", res.file, res.replaced, res.matches)
+			} else {
+				fmt.Printf("%s: replaced %s\n", res.file, plural(res.replaced, "match", "matches"))
+			}
+		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading input file:", err)
-		return
+	switch {
+	case matches == 0:
+		fmt.Println("No matches found.")
+	case dryRun:
+		fmt.Printf("%s in %s would be replaced.\n", plural(matches, "match", "matches"), plural(files, "file", "files"))
+	case replaced < matches:
+		fmt.Printf("Replaced %d of %s in %s.\n", replaced, plural(matches, "match", "matches"), plural(files, "file", "files"))
+	default:
+		fmt.Printf("Replaced %s in %s.\n", plural(replaced, "match", "matches"), plural(files, "file", "files"))
 	}
+}
 
-	fmt.Println("Replacement operation completed successfully.")
+// plural formats n with the singular or plural noun.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// colorOutput decides whether the diff is colored. auto colors it if
+// stdout is a terminal and NO_COLOR isn't set.
+func colorOutput(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		info, err := os.Stdout.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("invalid -color: %s (use auto, always or never)", mode)
+}
+
+// stringList is a flag.Value collecting repeated or comma separated values.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	if len(*l) == 0 {
+		return fmt.Errorf("empty pattern")
+	}
+	return nil
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Options select how the search string is matched.
+type Options struct {
+	// Regex treats the search as a Go RE2 regular expression and expands
+	// $1 and ${name} in the replacement.
+	Regex bool
+	// IgnoreCase matches regardless of case.
+	IgnoreCase bool
+	// WholeWord only matches at word boundaries.
+	WholeWord bool
+	// Multiline matches the whole file at once instead of line by line,
+	// so a match may span lines. ^ and $ still match at every line.
+	Multiline bool
+}
+
+// Replacer finds the matches of a search string and their replacements.
+type Replacer struct {
+	search    string
+	replace   string
+	re        *regexp.Regexp
+	expand    bool
+	multiline bool
+}
+
+// Edit replaces the bytes from Start to End of a text with New.
+type Edit struct {
+	Start, End int
+	New        string
+}
+
+// NewReplacer returns a replacer of search by replace.
+func NewReplacer(search, replace string, opts Options) (*Replacer, error) {
+	if search == "" {
+		return nil, fmt.Errorf("empty search string")
+	}
+	r := &Replacer{search: search, replace: replace, expand: opts.Regex, multiline: opts.Multiline}
+	if !opts.Regex && !opts.IgnoreCase && !opts.WholeWord {
+		return r, nil
+	}
+
+	expr := search
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(search)
+	}
+	if opts.WholeWord {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	flags := "m"
+	if opts.IgnoreCase {
+		flags += "i"
+	}
+	re, err := regexp.Compile("(?" + flags + ")" + expr)
+	if err != nil {
+		var serr *syntax.Error
+		if errors.As(err, &serr) {
+			return nil, fmt.Errorf("invalid regular expression %q: %s", search, serr.Code)
+		}
+		return nil, fmt.Errorf("invalid regular expression %q: %v", search, err)
+	}
+	r.re = re
+	return r, nil
+}
+
+// Edits returns the replacements in text, in order.
+func (r *Replacer) Edits(text string) []Edit {
+	if r.multiline {
+		return r.find(text, 0, nil)
+	}
+	var edits []Edit
+	offset := 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		// A final newline doesn't start another line.
+		if line == "" && offset > 0 {
+			break
+		}
+		content := strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		edits = r.find(content, offset, edits)
+		offset += len(line)
+	}
+	return edits
+}
+
+// find appends the replacements in s, which starts at offset of the text.
+func (r *Replacer) find(s string, offset int, edits []Edit) []Edit {
+	if r.re == nil {
+		for i := 0; ; {
+			j := strings.Index(s[i:], r.search)
+			if j < 0 {
+				return edits
+			}
+			i += j
+			edits = append(edits, Edit{Start: offset + i, End: offset + i + len(r.search), New: r.replace})
+			i += len(r.search)
+		}
+	}
+	for _, m := range r.re.FindAllStringSubmatchIndex(s, -1) {
+		repl := r.replace
+		if r.expand {
+			repl = string(r.re.ExpandString(nil, r.replace, s, m))
+		}
+		edits = append(edits, Edit{Start: offset + m[0], End: offset + m[1], New: repl})
+	}
+	return edits
+}
+
+// Apply returns text with the edits applied. The edits must be in order
+// and must not overlap.
+func Apply(text string, edits []Edit) string {
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		b.WriteString(text[last:e.Start])
+		b.WriteString(e.New)
+		last = e.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}